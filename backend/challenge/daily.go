@@ -0,0 +1,249 @@
+package challenge
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fafeitsch/city-knowledge-contest/backend/contest"
+)
+
+const (
+	DateFormat  = "2006-01-02"
+	dailyPrefix = "daily:"
+)
+
+type DailyResult struct {
+	Date       string    `json:"date"`
+	StreetList string    `json:"streetList"`
+	RoomKey    string    `json:"roomKey"`
+	ProfileId  string    `json:"profileId,omitempty"`
+	Name       string    `json:"name"`
+	Points     int       `json:"points"`
+	Correct    int       `json:"correct"`
+	Questions  int       `json:"questions"`
+	Finished   time.Time `json:"finished"`
+}
+
+type Streak struct {
+	Current  int    `json:"current"`
+	Best     int    `json:"best"`
+	LastDate string `json:"lastDate,omitempty"`
+}
+
+type Daily struct {
+	sync.RWMutex
+	file     *os.File
+	results  []DailyResult
+	rooms    map[string]bool
+	attempts map[string]bool
+}
+
+func Today() string {
+	return time.Now().UTC().Format(DateFormat)
+}
+
+func DailyChallenge(date string) string {
+	return dailyPrefix + date
+}
+
+func DailySeed(date string, streetList string) string {
+	return DailyChallenge(date) + ":" + streetList
+}
+
+func OpenDaily(path string) (*Daily, error) {
+	daily := &Daily{rooms: make(map[string]bool), attempts: make(map[string]bool)}
+	if path == "" {
+		return daily, nil
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("could not open daily challenge file \"%s\": %v", path, err)
+	}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var result DailyResult
+		if err := json.Unmarshal(scanner.Bytes(), &result); err != nil {
+			log.Printf("skipping unreadable entry in daily challenge file \"%s\": %v", path, err)
+			continue
+		}
+		daily.results = append(daily.results, result)
+		daily.rooms[result.RoomKey] = true
+		if result.ProfileId != "" {
+			daily.attempts[attempt(result.Date, result.StreetList, result.ProfileId)] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("could not read daily challenge file \"%s\": %v", path, err)
+	}
+	daily.file = file
+	return daily, nil
+}
+
+func (d *Daily) Close() error {
+	if d.file == nil {
+		return nil
+	}
+	return d.file.Close()
+}
+
+func (d *Daily) Archive(record contest.GameRecord) {
+	if !strings.HasPrefix(record.Challenge, dailyPrefix) || record.Options.StreetList == nil {
+		return
+	}
+	d.Lock()
+	defer d.Unlock()
+	if d.rooms[record.RoomKey] {
+		return
+	}
+	d.rooms[record.RoomKey] = true
+	for _, player := range record.Summary.Players {
+		result := DailyResult{
+			Date:       strings.TrimPrefix(record.Challenge, dailyPrefix),
+			StreetList: record.Options.StreetList.FileName,
+			RoomKey:    record.RoomKey,
+			ProfileId:  player.ProfileId,
+			Name:       player.Name,
+			Points:     player.Points,
+			Correct:    player.Correct,
+			Questions:  player.Questions,
+			Finished:   record.Finished,
+		}
+		d.results = append(d.results, result)
+		if d.file == nil {
+			continue
+		}
+		line, _ := json.Marshal(result)
+		if _, err := d.file.Write(append(line, '\n')); err != nil {
+			log.Printf("could not write daily challenge result of room \"%s\": %v", record.RoomKey, err)
+		}
+	}
+}
+
+func attempt(date string, streetList string, profile string) string {
+	return date + ":" + streetList + ":" + profile
+}
+
+func (d *Daily) Start(date string, streetList string, profile string) bool {
+	if profile == "" {
+		return true
+	}
+	d.Lock()
+	defer d.Unlock()
+	key := attempt(date, streetList, profile)
+	if d.attempts[key] {
+		return false
+	}
+	d.attempts[key] = true
+	return true
+}
+
+func (d *Daily) Played(date string, streetList string, profile string) bool {
+	if profile == "" {
+		return false
+	}
+	d.RLock()
+	defer d.RUnlock()
+	return d.attempts[attempt(date, streetList, profile)]
+}
+
+func (d *Daily) Ranking(date string, streetList string) []DailyResult {
+	d.RLock()
+	defer d.RUnlock()
+	profiles := make(map[string]bool)
+	result := make([]DailyResult, 0)
+	for _, entry := range d.results {
+		if entry.Date != date || entry.StreetList != streetList || entry.ProfileId == "" || profiles[entry.ProfileId] {
+			continue
+		}
+		profiles[entry.ProfileId] = true
+		result = append(result, entry)
+	}
+	sort.SliceStable(
+		result, func(i, j int) bool {
+			return ahead(result[i], result[j])
+		},
+	)
+	return result
+}
+
+func (d *Daily) Result(roomKey string) (DailyResult, bool) {
+	d.RLock()
+	defer d.RUnlock()
+	for _, entry := range d.results {
+		if entry.RoomKey == roomKey {
+			return entry, true
+		}
+	}
+	return DailyResult{}, false
+}
+
+func Rank(ranking []DailyResult, result DailyResult) int {
+	rank := 1
+	for _, entry := range ranking {
+		if entry.RoomKey == result.RoomKey && entry.ProfileId == result.ProfileId {
+			continue
+		}
+		if ahead(entry, result) {
+			rank = rank + 1
+		}
+	}
+	return rank
+}
+
+func ahead(first DailyResult, second DailyResult) bool {
+	if first.Points != second.Points {
+		return first.Points > second.Points
+	}
+	return first.Finished.Before(second.Finished)
+}
+
+func (d *Daily) Streak(profile string, today string) Streak {
+	d.RLock()
+	defer d.RUnlock()
+	days := make(map[string]bool)
+	for _, result := range d.results {
+		if result.ProfileId != "" && result.ProfileId == profile {
+			days[result.Date] = true
+		}
+	}
+	dates := make([]time.Time, 0, len(days))
+	for day := range days {
+		date, err := time.Parse(DateFormat, day)
+		if err == nil {
+			dates = append(dates, date)
+		}
+	}
+	if len(dates) == 0 {
+		return Streak{}
+	}
+	sort.Slice(
+		dates, func(i, j int) bool {
+			return dates[i].Before(dates[j])
+		},
+	)
+	streak := Streak{LastDate: dates[len(dates)-1].Format(DateFormat)}
+	run := 0
+	for index, date := range dates {
+		if index > 0 && date.Sub(dates[index-1]) == 24*time.Hour {
+			run = run + 1
+		} else {
+			run = 1
+		}
+		if run > streak.Best {
+			streak.Best = run
+		}
+	}
+	current, err := time.Parse(DateFormat, today)
+	if err == nil && current.Sub(dates[len(dates)-1]) <= 24*time.Hour {
+		streak.Current = run
+	}
+	return streak
+}
@@ -0,0 +1,205 @@
+package challenge
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fafeitsch/city-knowledge-contest/backend/contest"
+	"github.com/fafeitsch/city-knowledge-contest/backend/geodata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func dailyRecord(roomKey string, date string, finished time.Time, players ...contest.PlayerSummary) contest.GameRecord {
+	return contest.GameRecord{
+		RoomKey:   roomKey,
+		Challenge: DailyChallenge(date),
+		Options:   contest.RoomOptions{StreetList: &geodata.StreetList{FileName: "wuerzburg.json"}},
+		Summary:   contest.GameSummary{Game: 1, Players: players},
+		Finished:  finished,
+	}
+}
+
+func TestDailySeed(t *testing.T) {
+	assert.Equal(t, "daily:2022-06-01", DailyChallenge("2022-06-01"))
+	assert.Equal(t, "daily:2022-06-01:wuerzburg.json", DailySeed("2022-06-01", "wuerzburg.json"))
+	assert.NotEqual(t, DailySeed("2022-06-01", "wuerzburg.json"), DailySeed("2022-06-02", "wuerzburg.json"))
+	_, err := time.Parse(DateFormat, Today())
+	assert.NoError(t, err)
+}
+
+func TestDaily_Start(t *testing.T) {
+	tests := []struct {
+		name       string
+		profile    string
+		started    []string
+		wantStart  bool
+		wantPlayed bool
+	}{
+		{name: "first attempt", profile: "alice", wantStart: true, wantPlayed: true},
+		{name: "second attempt", profile: "alice", started: []string{"alice"}, wantPlayed: true},
+		{name: "other profile started", profile: "alice", started: []string{"bob"}, wantStart: true, wantPlayed: true},
+		{name: "anonymous player", profile: "", started: []string{""}, wantStart: true},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				daily, err := OpenDaily("")
+				require.NoError(t, err)
+				for _, profile := range tt.started {
+					daily.Start("2022-06-01", "wuerzburg.json", profile)
+				}
+				assert.Equal(t, tt.wantStart, daily.Start("2022-06-01", "wuerzburg.json", tt.profile))
+				assert.Equal(t, tt.wantPlayed, daily.Played("2022-06-01", "wuerzburg.json", tt.profile))
+				assert.False(t, daily.Played("2022-06-02", "wuerzburg.json", tt.profile))
+				assert.False(t, daily.Played("2022-06-01", "berlin.json", tt.profile))
+			},
+		)
+	}
+}
+
+func TestDaily_Archive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "daily.jsonl")
+	daily, err := OpenDaily(path)
+	require.NoError(t, err)
+	finished := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	record := dailyRecord("room", "2022-06-01", finished, contest.PlayerSummary{Name: "Alice", ProfileId: "alice", Points: 80})
+	daily.Archive(record)
+	daily.Archive(record)
+	friendly := record
+	friendly.RoomKey = "other"
+	friendly.Challenge = ""
+	daily.Archive(friendly)
+	assert.Len(t, daily.Ranking("2022-06-01", "wuerzburg.json"), 1)
+	require.NoError(t, daily.Close())
+
+	reopened, err := OpenDaily(path)
+	require.NoError(t, err)
+	defer func() { _ = reopened.Close() }()
+	ranking := reopened.Ranking("2022-06-01", "wuerzburg.json")
+	require.Len(t, ranking, 1)
+	assert.Equal(t, "Alice", ranking[0].Name)
+	assert.True(t, reopened.Played("2022-06-01", "wuerzburg.json", "alice"))
+	assert.False(t, reopened.Start("2022-06-01", "wuerzburg.json", "alice"))
+	reopened.Archive(record)
+	assert.Len(t, reopened.Ranking("2022-06-01", "wuerzburg.json"), 1)
+}
+
+func TestDaily_Ranking(t *testing.T) {
+	morning := time.Date(2022, 6, 1, 8, 0, 0, 0, time.UTC)
+	daily, err := OpenDaily("")
+	require.NoError(t, err)
+	daily.Archive(
+		dailyRecord(
+			"first", "2022-06-01", morning.Add(time.Hour),
+			contest.PlayerSummary{Name: "Alice", ProfileId: "alice", Points: 60},
+			contest.PlayerSummary{Name: "Guest", Points: 90},
+		),
+	)
+	daily.Archive(
+		dailyRecord(
+			"second", "2022-06-01", morning,
+			contest.PlayerSummary{Name: "Bob", ProfileId: "bob", Points: 60},
+			contest.PlayerSummary{Name: "Alice", ProfileId: "alice", Points: 100},
+		),
+	)
+	daily.Archive(dailyRecord("third", "2022-06-02", morning, contest.PlayerSummary{Name: "Carol", ProfileId: "carol", Points: 10}))
+	daily.Archive(dailyRecord("fourth", "2022-06-02", morning, contest.PlayerSummary{Name: "Guest", Points: 50}))
+	tests := []struct {
+		name       string
+		date       string
+		streetList string
+		want       []string
+	}{
+		{name: "first attempt per profile counts", date: "2022-06-01", streetList: "wuerzburg.json", want: []string{"Bob", "Alice"}},
+		{name: "other day", date: "2022-06-02", streetList: "wuerzburg.json", want: []string{"Carol"}},
+		{name: "other street list", date: "2022-06-01", streetList: "berlin.json", want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				names := make([]string, 0)
+				for _, result := range daily.Ranking(tt.date, tt.streetList) {
+					names = append(names, result.Name)
+				}
+				assert.Equal(t, tt.want, names)
+			},
+		)
+	}
+}
+
+func TestRank(t *testing.T) {
+	morning := time.Date(2022, 6, 1, 8, 0, 0, 0, time.UTC)
+	daily, err := OpenDaily("")
+	require.NoError(t, err)
+	daily.Archive(dailyRecord("alice", "2022-06-01", morning, contest.PlayerSummary{Name: "Alice", ProfileId: "alice", Points: 80}))
+	daily.Archive(dailyRecord("bob", "2022-06-01", morning.Add(time.Hour), contest.PlayerSummary{Name: "Bob", ProfileId: "bob", Points: 80}))
+	daily.Archive(dailyRecord("guest", "2022-06-01", morning.Add(2*time.Hour), contest.PlayerSummary{Name: "Guest", Points: 90}))
+	daily.Archive(dailyRecord("late", "2022-06-01", morning.Add(3*time.Hour), contest.PlayerSummary{Name: "Late", Points: 80}))
+	ranking := daily.Ranking("2022-06-01", "wuerzburg.json")
+	tests := []struct {
+		roomKey string
+		want    int
+	}{
+		{roomKey: "alice", want: 1},
+		{roomKey: "bob", want: 2},
+		{roomKey: "guest", want: 1},
+		{roomKey: "late", want: 3},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.roomKey, func(t *testing.T) {
+				result, ok := daily.Result(tt.roomKey)
+				require.True(t, ok)
+				assert.Equal(t, tt.want, Rank(ranking, result))
+			},
+		)
+	}
+	_, ok := daily.Result("unknown")
+	assert.False(t, ok)
+}
+
+func TestDaily_Streak(t *testing.T) {
+	tests := []struct {
+		name  string
+		dates []string
+		today string
+		want  Streak
+	}{
+		{name: "never played", today: "2022-06-10", want: Streak{}},
+		{name: "played today", dates: []string{"2022-06-10"}, today: "2022-06-10", want: Streak{Current: 1, Best: 1, LastDate: "2022-06-10"}},
+		{
+			name:  "played yesterday",
+			dates: []string{"2022-06-08", "2022-06-09"},
+			today: "2022-06-10",
+			want:  Streak{Current: 2, Best: 2, LastDate: "2022-06-09"},
+		},
+		{
+			name:  "broken streak",
+			dates: []string{"2022-06-01", "2022-06-02", "2022-06-03", "2022-06-07"},
+			today: "2022-06-10",
+			want:  Streak{Best: 3, LastDate: "2022-06-07"},
+		},
+		{
+			name:  "new streak after a gap",
+			dates: []string{"2022-06-01", "2022-06-02", "2022-06-03", "2022-06-09", "2022-06-10"},
+			today: "2022-06-10",
+			want:  Streak{Current: 2, Best: 3, LastDate: "2022-06-10"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				daily, err := OpenDaily("")
+				require.NoError(t, err)
+				for _, date := range tt.dates {
+					daily.Archive(dailyRecord(date, date, time.Now(), contest.PlayerSummary{Name: "Alice", ProfileId: "alice"}))
+					daily.Archive(dailyRecord("guest-"+date, date, time.Now(), contest.PlayerSummary{Name: "Guest"}))
+				}
+				assert.Equal(t, tt.want, daily.Streak("alice", tt.today))
+				assert.Equal(t, Streak{}, daily.Streak("", tt.today))
+			},
+		)
+	}
+}
@@ -0,0 +1,205 @@
+package challenge
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fafeitsch/city-knowledge-contest/backend/contest"
+	"github.com/fafeitsch/city-knowledge-contest/backend/geodata"
+	"github.com/fafeitsch/city-knowledge-contest/backend/keygen"
+)
+
+const friendPrefix = "friend:"
+
+type FriendResult struct {
+	RoomKey   string    `json:"roomKey"`
+	ProfileId string    `json:"profileId,omitempty"`
+	Name      string    `json:"name"`
+	Points    int       `json:"points"`
+	Correct   int       `json:"correct"`
+	Questions int       `json:"questions"`
+	Creator   bool      `json:"creator"`
+	Finished  time.Time `json:"finished"`
+}
+
+type Friend struct {
+	Id             string           `json:"id"`
+	Created        time.Time        `json:"created"`
+	CreatorName    string           `json:"creatorName"`
+	CreatorProfile string           `json:"creatorProfile,omitempty"`
+	CreatorRoom    string           `json:"creatorRoom"`
+	CreatorPlayer  string           `json:"creatorPlayer"`
+	StreetList     string           `json:"streetList"`
+	Streets        []geodata.Street `json:"streets"`
+	MaxAnswerTime  time.Duration    `json:"maxAnswerTime"`
+	LockIn         bool             `json:"lockIn"`
+	Results        []FriendResult   `json:"results"`
+}
+
+type Friends struct {
+	sync.RWMutex
+	path       string
+	challenges map[string]*Friend
+}
+
+func FriendChallenge(id string) string {
+	return friendPrefix + id
+}
+
+func OpenFriends(path string) (*Friends, error) {
+	friends := &Friends{path: path, challenges: make(map[string]*Friend)}
+	if path == "" {
+		return friends, nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return friends, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read friend challenge file \"%s\": %v", path, err)
+	}
+	stored := make([]*Friend, 0)
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("could not parse friend challenge file \"%s\": %v", path, err)
+	}
+	for _, challenge := range stored {
+		friends.challenges[challenge.Id] = challenge
+	}
+	return friends, nil
+}
+
+func (f *Friends) Create(challenge Friend) (Friend, error) {
+	challenge.Id = keygen.RoomKey()
+	challenge.Created = time.Now()
+	f.Lock()
+	defer f.Unlock()
+	f.challenges[challenge.Id] = &challenge
+	return challenge, f.save()
+}
+
+func (f *Friends) Get(id string) (Friend, bool) {
+	f.RLock()
+	defer f.RUnlock()
+	challenge, ok := f.challenges[id]
+	if !ok {
+		return Friend{}, false
+	}
+	result := *challenge
+	result.Results = scoreboard(challenge.Results)
+	return result, true
+}
+
+func (f *Friends) Played(id string, profile string) bool {
+	f.RLock()
+	defer f.RUnlock()
+	challenge, ok := f.challenges[id]
+	if !ok || profile == "" {
+		return false
+	}
+	for _, result := range challenge.Results {
+		if result.ProfileId == profile {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *Friends) Record(record contest.GameRecord) (Friend, []FriendResult, error) {
+	if !strings.HasPrefix(record.Challenge, friendPrefix) {
+		return Friend{}, nil, nil
+	}
+	f.Lock()
+	defer f.Unlock()
+	challenge, ok := f.challenges[strings.TrimPrefix(record.Challenge, friendPrefix)]
+	if !ok {
+		return Friend{}, nil, nil
+	}
+	for _, result := range challenge.Results {
+		if result.RoomKey == record.RoomKey {
+			return Friend{}, nil, nil
+		}
+	}
+	creatorPoints := challenge.CreatorPoints()
+	recorded := make([]FriendResult, 0, len(record.Summary.Players))
+	for _, player := range record.Summary.Players {
+		result := FriendResult{
+			RoomKey:   record.RoomKey,
+			ProfileId: player.ProfileId,
+			Name:      player.Name,
+			Points:    player.Points,
+			Correct:   player.Correct,
+			Questions: player.Questions,
+			Finished:  record.Finished,
+		}
+		challenge.Results = append(challenge.Results, result)
+		recorded = append(recorded, result)
+	}
+	beaten := make([]FriendResult, 0)
+	for _, result := range recorded {
+		if result.Points > creatorPoints {
+			beaten = append(beaten, result)
+		}
+	}
+	copied := *challenge
+	copied.Results = scoreboard(challenge.Results)
+	return copied, beaten, f.save()
+}
+
+func (f Friend) CreatorPoints() int {
+	for _, result := range f.Results {
+		if result.Creator {
+			return result.Points
+		}
+	}
+	return 0
+}
+
+func (f Friend) Rank(roomKey string) int {
+	for index, result := range f.Results {
+		if result.RoomKey == roomKey && !result.Creator {
+			return index + 1
+		}
+	}
+	return 0
+}
+
+func (f *Friends) save() error {
+	if f.path == "" {
+		return nil
+	}
+	stored := make([]*Friend, 0, len(f.challenges))
+	for _, challenge := range f.challenges {
+		stored = append(stored, challenge)
+	}
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return fmt.Errorf("could not serialize friend challenges: %v", err)
+	}
+	temporary := f.path + ".tmp"
+	if err := os.WriteFile(temporary, data, 0644); err != nil {
+		return fmt.Errorf("could not write friend challenge file \"%s\": %v", f.path, err)
+	}
+	if err := os.Rename(temporary, f.path); err != nil {
+		return fmt.Errorf("could not write friend challenge file \"%s\": %v", f.path, err)
+	}
+	return nil
+}
+
+func scoreboard(results []FriendResult) []FriendResult {
+	sorted := make([]FriendResult, len(results))
+	copy(sorted, results)
+	sort.SliceStable(
+		sorted, func(i, j int) bool {
+			if sorted[i].Points != sorted[j].Points {
+				return sorted[i].Points > sorted[j].Points
+			}
+			return sorted[i].Finished.Before(sorted[j].Finished)
+		},
+	)
+	return sorted
+}
@@ -0,0 +1,190 @@
+package challenge
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fafeitsch/city-knowledge-contest/backend/contest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func friendRecord(id string, roomKey string, finished time.Time, players ...contest.PlayerSummary) contest.GameRecord {
+	return contest.GameRecord{
+		RoomKey:   roomKey,
+		Challenge: FriendChallenge(id),
+		Summary:   contest.GameSummary{Game: 1, Players: players},
+		Finished:  finished,
+	}
+}
+
+func createFriend(t *testing.T, friends *Friends, creatorPoints int) Friend {
+	created, err := friends.Create(
+		Friend{
+			CreatorName: "Alice",
+			CreatorRoom: "creator",
+			StreetList:  "wuerzburg.json",
+			Results: []FriendResult{
+				{RoomKey: "creator", Name: "Alice", ProfileId: "alice", Points: creatorPoints, Creator: true},
+			},
+		},
+	)
+	require.NoError(t, err)
+	require.NotEmpty(t, created.Id)
+	return created
+}
+
+func TestFriends_Record(t *testing.T) {
+	tests := []struct {
+		name       string
+		challenge  func(id string) string
+		players    []contest.PlayerSummary
+		wantBeaten []string
+		wantRanks  []string
+	}{
+		{
+			name:       "beaten by one player",
+			players:    []contest.PlayerSummary{{Name: "Bob", Points: 90}, {Name: "Carol", Points: 40}},
+			wantBeaten: []string{"Bob"},
+			wantRanks:  []string{"Bob", "Alice", "Carol"},
+		},
+		{
+			name:       "tie does not beat the creator",
+			players:    []contest.PlayerSummary{{Name: "Bob", Points: 60}},
+			wantBeaten: []string{},
+			wantRanks:  []string{"Alice", "Bob"},
+		},
+		{
+			name: "unknown challenge",
+			challenge: func(string) string {
+				return FriendChallenge("unknown")
+			},
+			players: []contest.PlayerSummary{{Name: "Bob"}},
+		},
+		{
+			name: "no challenge",
+			challenge: func(string) string {
+				return ""
+			},
+			players: []contest.PlayerSummary{{Name: "Bob"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				friends, err := OpenFriends("")
+				require.NoError(t, err)
+				created := createFriend(t, friends, 60)
+				record := friendRecord(created.Id, "room", time.Now(), tt.players...)
+				if tt.challenge != nil {
+					record.Challenge = tt.challenge(created.Id)
+				}
+				challenge, beaten, err := friends.Record(record)
+				require.NoError(t, err)
+				var beatenNames []string
+				if beaten != nil {
+					beatenNames = make([]string, 0)
+				}
+				for _, result := range beaten {
+					beatenNames = append(beatenNames, result.Name)
+				}
+				assert.Equal(t, tt.wantBeaten, beatenNames)
+				var ranks []string
+				for _, result := range challenge.Results {
+					ranks = append(ranks, result.Name)
+				}
+				assert.Equal(t, tt.wantRanks, ranks)
+				if tt.wantRanks != nil {
+					assert.Equal(t, 60, challenge.CreatorPoints())
+				}
+			},
+		)
+	}
+}
+
+func TestFriends_RecordOnce(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "friends.json")
+	friends, err := OpenFriends(path)
+	require.NoError(t, err)
+	created := createFriend(t, friends, 50)
+	record := friendRecord(created.Id, "room", time.Now(), contest.PlayerSummary{Name: "Bob", ProfileId: "bob", Points: 70})
+	_, beaten, err := friends.Record(record)
+	require.NoError(t, err)
+	assert.Len(t, beaten, 1)
+	_, beaten, err = friends.Record(record)
+	require.NoError(t, err)
+	assert.Nil(t, beaten)
+
+	reopened, err := OpenFriends(path)
+	require.NoError(t, err)
+	challenge, ok := reopened.Get(created.Id)
+	require.True(t, ok)
+	assert.Len(t, challenge.Results, 2)
+	assert.Equal(t, "Bob", challenge.Results[0].Name)
+	_, ok = reopened.Get("unknown")
+	assert.False(t, ok)
+}
+
+func TestFriends_Played(t *testing.T) {
+	friends, err := OpenFriends("")
+	require.NoError(t, err)
+	created := createFriend(t, friends, 50)
+	_, _, err = friends.Record(
+		friendRecord(created.Id, "room", time.Now(), contest.PlayerSummary{Name: "Bob", ProfileId: "bob"}),
+	)
+	require.NoError(t, err)
+	tests := []struct {
+		name    string
+		id      string
+		profile string
+		want    bool
+	}{
+		{name: "creator", id: created.Id, profile: "alice", want: true},
+		{name: "challenger", id: created.Id, profile: "bob", want: true},
+		{name: "not yet played", id: created.Id, profile: "carol"},
+		{name: "anonymous player", id: created.Id},
+		{name: "unknown challenge", id: "unknown", profile: "bob"},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				assert.Equal(t, tt.want, friends.Played(tt.id, tt.profile))
+			},
+		)
+	}
+}
+
+func TestFriend_Rank(t *testing.T) {
+	now := time.Now()
+	challenge := Friend{
+		Results: scoreboard(
+			[]FriendResult{
+				{RoomKey: "creator", Points: 50, Creator: true, Finished: now},
+				{RoomKey: "late", Points: 80, Finished: now.Add(time.Hour)},
+				{RoomKey: "early", Points: 80, Finished: now.Add(time.Minute)},
+				{RoomKey: "low", Points: 10, Finished: now},
+			},
+		),
+	}
+	tests := []struct {
+		name    string
+		roomKey string
+		want    int
+	}{
+		{name: "earlier result wins ties", roomKey: "early", want: 1},
+		{name: "later result", roomKey: "late", want: 2},
+		{name: "below the creator", roomKey: "low", want: 4},
+		{name: "creator is not ranked", roomKey: "creator"},
+		{name: "unknown room", roomKey: "unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				assert.Equal(t, tt.want, challenge.Rank(tt.roomKey))
+			},
+		)
+	}
+	assert.Equal(t, 50, challenge.CreatorPoints())
+	assert.Zero(t, Friend{}.CreatorPoints())
+}
@@ -112,6 +112,41 @@ var streetSelectionSeedFlag = &cli.StringFlag{
 	Usage:       "If provided, the seed is used for street selection. Usually only needed for E2E testing.",
 	Destination: &streetSelectionSeed,
 }
+var historyFile string
+var historyFileFlag = &cli.StringFlag{
+	Name:        "historyFile",
+	Value:       "",
+	Usage:       "Path to the file in which finished games are stored. If empty, the history is kept in memory only.",
+	Destination: &historyFile,
+}
+var profileFile string
+var profileFileFlag = &cli.StringFlag{
+	Name:        "profileFile",
+	Value:       "",
+	Usage:       "Path to the file in which player profiles are stored. If empty, profiles are kept in memory only.",
+	Destination: &profileFile,
+}
+var ratingFile string
+var ratingFileFlag = &cli.StringFlag{
+	Name:        "ratingFile",
+	Value:       "",
+	Usage:       "Path to the file in which rating changes of profiles are stored. If empty, ratings are kept in memory only.",
+	Destination: &ratingFile,
+}
+var dailyFile string
+var dailyFileFlag = &cli.StringFlag{
+	Name:        "dailyFile",
+	Value:       "",
+	Usage:       "Path to the file in which daily challenge results are stored. If empty, results are kept in memory only.",
+	Destination: &dailyFile,
+}
+var challengeFile string
+var challengeFileFlag = &cli.StringFlag{
+	Name:        "challengeFile",
+	Value:       "",
+	Usage:       "Path to the file in which friend challenges and their results are stored. If empty, challenges are kept in memory only.",
+	Destination: &challengeFile,
+}
 
 func main() {
 	app := cli.App{
@@ -130,6 +165,11 @@ func main() {
 			dataProtectionFileFlag,
 			imprintFileFlag,
 			streetSelectionSeedFlag,
+			historyFileFlag,
+			profileFileFlag,
+			ratingFileFlag,
+			dailyFileFlag,
+			challengeFileFlag,
 		},
 		HideHelpCommand: true,
 		Action: func(context *cli.Context) error {
@@ -143,6 +183,11 @@ func main() {
 					ImprintFile:        imprintFile,
 					Version:            version,
 					Seed:               streetSelectionSeed,
+					HistoryFile:        historyFile,
+					ProfileFile:        profileFile,
+					RatingFile:         ratingFile,
+					DailyFile:          dailyFile,
+					ChallengeFile:      challengeFile,
 				},
 			)
 			keygen.SetPlayerKeyLength(playerKeyLength)
@@ -157,6 +202,11 @@ func main() {
 			log.Printf("Using data protection file at \"%s\"", dataProtectionFile)
 			log.Printf("Using imprint file at \"%s\"", imprintFile)
 			log.Printf("Using street selection seed \"%s\"", streetSelectionSeed)
+			log.Printf("Using history file at \"%s\"", historyFile)
+			log.Printf("Using profile file at \"%s\"", profileFile)
+			log.Printf("Using rating file at \"%s\"", ratingFile)
+			log.Printf("Using daily challenge file at \"%s\"", dailyFile)
+			log.Printf("Using friend challenge file at \"%s\"", challengeFile)
 			if sslKey != "" && sslCert != "" {
 				log.Printf("SSL key file: %s", sslKey)
 				log.Printf("SSL certificate file: %s", sslCert)
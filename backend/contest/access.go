@@ -0,0 +1,85 @@
+package contest
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"fmt"
+	"time"
+
+	"github.com/fafeitsch/city-knowledge-contest/backend/keygen"
+	"golang.org/x/crypto/scrypt"
+)
+
+type Invite struct {
+	Token   string
+	Expires time.Time
+}
+
+func (r *Room) SetPassword(password string) {
+	if password == "" {
+		r.passwordSalt = nil
+		r.passwordHash = nil
+		return
+	}
+	r.passwordSalt = make([]byte, 16)
+	_, _ = rand.Read(r.passwordSalt)
+	r.passwordHash = HashPassword(r.passwordSalt, password)
+}
+
+func (r *Room) PasswordSalt() []byte {
+	return r.passwordSalt
+}
+
+func (r *Room) HasPassword() bool {
+	return r.passwordHash != nil
+}
+
+func (r *Room) SetInviteOnly(inviteOnly bool) {
+	r.inviteOnly = inviteOnly
+}
+
+func (r *Room) InviteOnly() bool {
+	return r.inviteOnly
+}
+
+func (r *Room) CreateInvite(validity time.Duration) Invite {
+	r.removeExpiredInvites()
+	invite := Invite{Token: keygen.PlayerKey(), Expires: time.Now().Add(validity)}
+	r.invites[invite.Token] = invite.Expires
+	return invite
+}
+
+func (r *Room) Admit(passwordHash []byte, inviteToken string) bool {
+	r.removeExpiredInvites()
+	if _, ok := r.invites[inviteToken]; ok {
+		delete(r.invites, inviteToken)
+		return true
+	}
+	if r.inviteOnly {
+		return false
+	}
+	if r.passwordHash == nil {
+		return true
+	}
+	return subtle.ConstantTimeCompare(r.passwordHash, passwordHash) == 1
+}
+
+func (r *Room) removeExpiredInvites() {
+	now := time.Now()
+	for token, expires := range r.invites {
+		if now.After(expires) {
+			delete(r.invites, token)
+		}
+	}
+}
+
+func HashPassword(salt []byte, password string) []byte {
+	if salt == nil {
+		return nil
+	}
+	hash, err := scrypt.Key([]byte(password), salt, 1<<15, 8, 1, 32)
+	if err != nil {
+		panic(fmt.Sprintf("could not hash password: %v", err))
+	}
+	return hash
+}
@@ -0,0 +1,71 @@
+package contest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoom_Admit(t *testing.T) {
+	tests := []struct {
+		name       string
+		password   string
+		inviteOnly bool
+		attempt    string
+		invite     bool
+		validity   time.Duration
+		want       bool
+	}{
+		{name: "open room", want: true},
+		{name: "right password", password: "secret", attempt: "secret", want: true},
+		{name: "wrong password", password: "secret", attempt: "guess"},
+		{name: "missing password", password: "secret"},
+		{name: "invite instead of password", password: "secret", invite: true, validity: time.Hour, want: true},
+		{name: "expired invite", password: "secret", invite: true, validity: -time.Second},
+		{name: "invite only without invite", inviteOnly: true},
+		{name: "invite only with password", password: "secret", inviteOnly: true, attempt: "secret"},
+		{name: "invite only with invite", inviteOnly: true, invite: true, validity: time.Hour, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				room := newTestRoom(nil)
+				room.SetPassword(tt.password)
+				room.SetInviteOnly(tt.inviteOnly)
+				assert.Equal(t, tt.password != "", room.HasPassword())
+				assert.Equal(t, tt.inviteOnly, room.InviteOnly())
+				token := ""
+				if tt.invite {
+					token = room.CreateInvite(tt.validity).Token
+				}
+				assert.Equal(t, tt.want, room.Admit(HashPassword(room.PasswordSalt(), tt.attempt), token))
+			},
+		)
+	}
+}
+
+func TestRoom_AdmitInviteOnce(t *testing.T) {
+	room := newTestRoom(nil)
+	room.SetInviteOnly(true)
+	invite := room.CreateInvite(time.Hour)
+	assert.True(t, room.Admit(nil, invite.Token))
+	assert.False(t, room.Admit(nil, invite.Token))
+}
+
+func TestRoom_SetPassword(t *testing.T) {
+	room := newTestRoom(nil)
+	room.SetPassword("secret")
+	first := room.passwordHash
+	assert.NotContains(t, string(first), "secret")
+	room.SetPassword("secret")
+	assert.NotEqual(t, first, room.passwordHash)
+	assert.True(t, room.Admit(HashPassword(room.PasswordSalt(), "secret"), ""))
+	stale := HashPassword(room.PasswordSalt(), "secret")
+	room.SetPassword("secret")
+	assert.False(t, room.Admit(stale, ""))
+	room.SetPassword("")
+	assert.False(t, room.HasPassword())
+	assert.Nil(t, HashPassword(room.PasswordSalt(), "secret"))
+	assert.True(t, room.Admit(nil, ""))
+}
@@ -0,0 +1,60 @@
+package contest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func playReviewedQuestion(t *testing.T) (*Room, Player, *testNotifier, time.Time) {
+	room := newTestRoom(
+		func(options *RoomOptions) {
+			options.AutoAdvance = true
+			options.ReviewTime = 3 * time.Second
+		},
+	)
+	alice, notifier := joinConnected(room, "Alice")
+	startGame(t, room, alice.Key)
+	answered := time.Now()
+	answer(t, room, alice.Key, rightGuess)
+	notifier.await(t, "questionResults", 1)
+	return room, alice, notifier, answered
+}
+
+func TestRoom_AutoAdvanceAfterReviewTime(t *testing.T) {
+	t.Parallel()
+	room, alice, notifier, answered := playReviewedQuestion(t)
+	notifier.awaitEnd(t)
+	assert.GreaterOrEqual(t, time.Since(answered), 3*time.Second)
+	assert.Equal(t, []any{3 * time.Second, 2 * time.Second, time.Second}, notifier.all("nextQuestionIn"))
+	assert.Zero(t, notifier.count("playerReady"))
+	room.Lock()
+	replay := room.Replay(alice.Key, 0)
+	room.Unlock()
+	replayed := &testNotifier{}
+	replay(replayed)
+	assert.Equal(t, 1, replayed.count("questionResults"))
+	assert.Zero(t, replayed.count("nextQuestionIn"))
+}
+
+func TestRoom_AutoAdvanceWhenEveryoneIsReady(t *testing.T) {
+	t.Parallel()
+	room, alice, notifier, _ := playReviewedQuestion(t)
+	require.Eventually(
+		t, func() bool {
+			room.Lock()
+			defer room.Unlock()
+			if !room.CanBeAdvanced() {
+				return false
+			}
+			room.VoteReady(alice.Key)
+			return true
+		}, 5*time.Second, 10*time.Millisecond,
+	)
+	ended := notifier.awaitEnd(t)
+	assert.Equal(t, "finished", ended.reason)
+	assert.Equal(t, 1, notifier.count("playerReady"))
+	assert.Equal(t, 3*time.Second, notifier.all("nextQuestionIn")[0])
+}
@@ -0,0 +1,33 @@
+package contest
+
+import "time"
+
+type GameRecord struct {
+	RoomKey   string
+	Challenge string
+	Options   RoomOptions
+	Summary   GameSummary
+	Finished  time.Time
+}
+
+type Archive interface {
+	Archive(record GameRecord)
+}
+
+func (r *Room) SetArchive(archive Archive) {
+	r.archive = archive
+}
+
+func (r *Room) archiveGame(summary GameSummary) {
+	if r.archive == nil {
+		return
+	}
+	record := GameRecord{
+		RoomKey:   r.key,
+		Challenge: r.challenge,
+		Options:   r.options,
+		Summary:   summary,
+		Finished:  time.Now(),
+	}
+	go r.archive.Archive(record)
+}
@@ -0,0 +1,116 @@
+package contest
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testArchive struct {
+	mutex   sync.Mutex
+	records []GameRecord
+}
+
+func (a *testArchive) Archive(record GameRecord) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.records = append(a.records, record)
+}
+
+func (a *testArchive) await(t *testing.T, count int) []GameRecord {
+	require.Eventually(
+		t, func() bool {
+			a.mutex.Lock()
+			defer a.mutex.Unlock()
+			return len(a.records) >= count
+		}, 10*time.Second, 10*time.Millisecond,
+	)
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return append([]GameRecord{}, a.records...)
+}
+
+func TestRoom_Archive(t *testing.T) {
+	t.Parallel()
+	archive := &testArchive{}
+	room := newTestRoom(nil)
+	room.SetArchive(archive)
+	alice, notifier := joinConnected(room, "Alice")
+	bob, _ := joinConnected(room, "Bob")
+	startGame(t, room, alice.Key)
+	answer(t, room, alice.Key, rightGuess)
+	answer(t, room, bob.Key, wrongGuess)
+	awaitAdvance(t, room)
+	ended := notifier.awaitEnd(t)
+
+	records := archive.await(t, 1)
+	require.Len(t, records, 1)
+	assert.Equal(t, room.Key(), records[0].RoomKey)
+	assert.Equal(t, "test.json", records[0].Options.StreetList.FileName)
+	assert.Equal(t, ended.summary, records[0].Summary)
+	assert.False(t, records[0].Finished.IsZero())
+}
+
+func TestRoom_summarizeCarriedPoints(t *testing.T) {
+	tests := []struct {
+		name        string
+		carried     int
+		total       int
+		wantPoints  int
+		wantCarried int
+	}{
+		{name: "fresh game", total: 80, wantPoints: 80},
+		{name: "carried and earned", carried: 50, total: 80, wantPoints: 30, wantCarried: 50},
+		{name: "carried only", carried: 50, total: 50, wantCarried: 50},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				room := newTestRoom(nil)
+				alice, _ := joinConnected(room, "Alice")
+				room.carriedPoints = map[string]int{alice.Key: tt.carried}
+				room.questionRecords = []QuestionRecord{{Answers: []AnswerRecord{{PlayerKey: alice.Key}}}}
+				summary := room.summarize(map[string]int{alice.Key: tt.total})
+				require.Len(t, summary.Players, 1)
+				assert.Equal(t, tt.wantPoints, summary.Players[0].Points)
+				assert.Equal(t, tt.wantCarried, summary.Players[0].CarriedPoints)
+			},
+		)
+	}
+}
+
+func TestRoom_ArchiveRematchWithKeptScores(t *testing.T) {
+	t.Parallel()
+	archive := &testArchive{}
+	room := newTestRoom(nil)
+	room.SetArchive(archive)
+	alice, notifier := joinConnected(room, "Alice")
+	bob, _ := joinConnected(room, "Bob")
+	startGame(t, room, alice.Key)
+	answer(t, room, alice.Key, rightGuess)
+	answer(t, room, bob.Key, wrongGuess)
+	awaitAdvance(t, room)
+	first := notifier.awaitEnd(t)
+	room.Lock()
+	require.True(t, room.CanRematch())
+	room.Rematch(alice.Key, true)
+	room.SetStreets(testStreets(1))
+	room.Unlock()
+	startGame(t, room, alice.Key)
+	answer(t, room, alice.Key, wrongGuess)
+	answer(t, room, bob.Key, wrongGuess)
+	awaitAdvance(t, room)
+	ended := notifier.await(t, "gameEnded", 2)[1].(gameEnded)
+
+	assert.Equal(t, first.points[alice.Key], ended.points[alice.Key])
+	records := archive.await(t, 2)
+	assert.Equal(t, room.Key(), records[1].RoomKey)
+	assert.Equal(t, 2, records[1].Summary.Game)
+	for _, player := range records[1].Summary.Players {
+		assert.Zero(t, player.Points, player.Name)
+		assert.Equal(t, first.points[player.PlayerKey], player.CarriedPoints, player.Name)
+	}
+}
@@ -0,0 +1,93 @@
+package contest
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fafeitsch/city-knowledge-contest/backend/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoom_PlayBlitz(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		answers  map[string][]types.Coordinate
+		recorded bool
+	}{
+		{
+			name: "recorded streets",
+			answers: map[string][]types.Coordinate{
+				"Alice": {rightGuess, rightGuess, rightGuess},
+				"Bob":   {wrongGuess},
+			},
+			recorded: true,
+		},
+		{
+			name: "random streets",
+			answers: map[string][]types.Coordinate{
+				"Alice": {rightGuess, wrongGuess, rightGuess, rightGuess},
+				"Bob":   {rightGuess, rightGuess},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(
+			tt.name, func(t *testing.T) {
+				t.Parallel()
+				room := newTestRoom(
+					func(options *RoomOptions) {
+						options.Mode = BlitzMode
+						options.GameDuration = time.Minute
+					},
+				)
+				if tt.recorded {
+					room.SetStreets(testStreets(20))
+				} else {
+					room.SetStreets(nil)
+				}
+				players := make(map[string]Player)
+				notifiers := make(map[string]*testNotifier)
+				for name := range tt.answers {
+					players[name], notifiers[name] = joinConnected(room, name)
+				}
+				startGame(t, room, players["Alice"].Key)
+				expected := make(map[string]int)
+				var mutex sync.Mutex
+				var streams sync.WaitGroup
+				for name, guesses := range tt.answers {
+					streams.Add(1)
+					go func(name string, guesses []types.Coordinate) {
+						defer streams.Done()
+						for round, guess := range guesses {
+							notifiers[name].await(t, "question", round+1)
+							points := answer(t, room, players[name].Key, guess)
+							mutex.Lock()
+							expected[players[name].Key] = expected[players[name].Key] + points
+							mutex.Unlock()
+						}
+					}(name, guesses)
+				}
+				streams.Wait()
+				for name, guesses := range tt.answers {
+					notifiers[name].await(t, "questionResults", len(guesses))
+				}
+				require.NoError(t, room.Close())
+				ended := notifiers["Alice"].awaitEnd(t)
+				assert.Equal(t, "finished", ended.reason)
+				for name, guesses := range tt.answers {
+					assert.Equal(t, expected[players[name].Key], ended.points[players[name].Key], name)
+					assert.Equal(t, time.Minute, notifiers[name].all("gameDeadline")[0])
+					for _, result := range notifiers[name].all("questionResults") {
+						assert.Len(t, result.(QuestionResult).PointDelta, 1)
+					}
+					assert.GreaterOrEqual(t, notifiers[name].count("question"), len(guesses))
+				}
+				assert.Greater(t, ended.points[players["Alice"].Key], 0)
+			},
+		)
+	}
+}
@@ -0,0 +1,11 @@
+package contest
+
+const DefaultMaxPlayers = 20
+
+func (r *Room) IsFull() bool {
+	return len(r.players) >= r.options.MaxPlayers
+}
+
+func (r *Room) HasEnoughPlayers() bool {
+	return len(r.players) >= r.options.MinPlayersToStart
+}
@@ -0,0 +1,73 @@
+package contest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoom_IsFull(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxPlayers int
+		minPlayers int
+		players    int
+		wantFull   bool
+		wantEnough bool
+	}{
+		{name: "empty", maxPlayers: 2, minPlayers: 1},
+		{name: "enough players", maxPlayers: 3, minPlayers: 2, players: 2, wantEnough: true},
+		{name: "too few players", maxPlayers: 3, minPlayers: 3, players: 2},
+		{name: "full", maxPlayers: 2, minPlayers: 1, players: 2, wantFull: true, wantEnough: true},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				room := newTestRoom(
+					func(options *RoomOptions) {
+						options.MaxPlayers = tt.maxPlayers
+						options.MinPlayersToStart = tt.minPlayers
+					},
+				)
+				for index := 0; index < tt.players; index++ {
+					joinConnected(room, "Player")
+				}
+				assert.Equal(t, tt.wantFull, room.IsFull())
+				assert.Equal(t, tt.wantEnough, room.HasEnoughPlayers())
+			},
+		)
+	}
+}
+
+func TestRoomOptions_ErrorsCapacity(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxPlayers int
+		minPlayers int
+		want       []string
+	}{
+		{name: "valid", maxPlayers: 20, minPlayers: 1},
+		{name: "no players allowed", maxPlayers: 0, minPlayers: 0, want: []string{"maxPlayersToSmall", "minPlayersToStartToSmall"}},
+		{name: "too many players", maxPlayers: 101, minPlayers: 1, want: []string{"maxPlayersToBig"}},
+		{name: "minimum above maximum", maxPlayers: 2, minPlayers: 3, want: []string{"minPlayersToStartToBig"}},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				room := newTestRoom(
+					func(options *RoomOptions) {
+						options.MaxPlayers = tt.maxPlayers
+						options.MinPlayersToStart = tt.minPlayers
+					},
+				)
+				errors := room.options.Errors()
+				for _, want := range tt.want {
+					assert.Contains(t, errors, want)
+				}
+				if len(tt.want) == 0 {
+					assert.Empty(t, errors)
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,64 @@
+package contest
+
+import "github.com/fafeitsch/city-knowledge-contest/backend/geodata"
+
+type ChallengeResult struct {
+	Challenge     string `json:"challenge"`
+	Name          string `json:"name"`
+	Points        int    `json:"points"`
+	CreatorPoints int    `json:"creatorPoints"`
+	Rank          int    `json:"rank"`
+	Participants  int    `json:"participants"`
+}
+
+type DailyStanding struct {
+	Date         string              `json:"date"`
+	ListFileName string              `json:"listFileName"`
+	Points       int                 `json:"points"`
+	Ranked       bool                `json:"ranked"`
+	Rank         int                 `json:"rank"`
+	Participants int                 `json:"participants"`
+	Ranking      []DailyRankingEntry `json:"ranking"`
+	Streak       int                 `json:"streak"`
+	BestStreak   int                 `json:"bestStreak"`
+}
+
+type DailyRankingEntry struct {
+	Name    string `json:"name"`
+	Points  int    `json:"points"`
+	Correct int    `json:"correct"`
+}
+
+func (r *Room) SetChallenge(challenge string) {
+	r.challenge = challenge
+}
+
+func (r *Room) Challenge() string {
+	return r.challenge
+}
+
+func (r *Room) SetStreets(streets []geodata.Street) {
+	r.streets = streets
+	r.nextStreet = 0
+}
+
+func (r *Room) NotifyChallengeBeaten(playerKey string, profile string, result ChallengeResult) {
+	for key, player := range r.players {
+		if key != playerKey && (profile == "" || player.Profile != profile) {
+			continue
+		}
+		r.notifyPlayer(
+			key, func(player Player) {
+				player.NotifyChallengeBeaten(result)
+			},
+		)
+	}
+}
+
+func (r *Room) NotifyDailyStanding(standing DailyStanding) {
+	r.notifyPlayers(
+		func(player Player) {
+			player.NotifyDailyStanding(standing)
+		},
+	)
+}
@@ -0,0 +1,49 @@
+package contest
+
+import (
+	"testing"
+
+	"github.com/fafeitsch/city-knowledge-contest/backend/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoom_PlayCoop(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		answers []types.Coordinate
+		reason  string
+		solved  bool
+	}{
+		{name: "solved by the first answer", answers: []types.Coordinate{rightGuess}, reason: "coopSucceeded", solved: true},
+		{name: "solved by the second answer", answers: []types.Coordinate{wrongGuess, rightGuess}, reason: "coopSucceeded", solved: true},
+		{name: "not solved", answers: []types.Coordinate{wrongGuess, wrongGuess}, reason: "coopFailed"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(
+			tt.name, func(t *testing.T) {
+				t.Parallel()
+				room := newTestRoom(
+					func(options *RoomOptions) {
+						options.Mode = CoopMode
+						options.CoopTarget = 1
+					},
+				)
+				alice, notifier := joinConnected(room, "Alice")
+				bob, _ := joinConnected(room, "Bob")
+				startGame(t, room, alice.Key)
+				players := []Player{alice, bob}
+				for index, guess := range tt.answers {
+					answer(t, room, players[index].Key, guess)
+				}
+				ended := notifier.awaitEnd(t)
+				assert.Equal(t, tt.reason, ended.reason)
+				assert.Equal(t, ended.points[alice.Key], ended.points[bob.Key])
+				assert.Equal(t, tt.solved, ended.points[alice.Key] > 0)
+				result := notifier.await(t, "questionResults", 1)[0].(QuestionResult)
+				assert.Equal(t, map[bool]int{true: 1}[tt.solved], result.SolvedQuestions)
+			},
+		)
+	}
+}
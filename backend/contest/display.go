@@ -0,0 +1,111 @@
+package contest
+
+import (
+	"sort"
+	"time"
+)
+
+type Display interface {
+	Notifier
+	NotifyQuestionDeadline(questionNumber int, deadline time.Time)
+	NotifyAnswerCount(answered int, total int)
+	NotifyScoreboard(scoreboard []ScoreboardEntry)
+}
+
+type ScoreboardEntry struct {
+	PlayerKey  string `json:"playerKey"`
+	Name       string `json:"name"`
+	Points     int    `json:"points"`
+	Rank       int    `json:"rank"`
+	RankChange int    `json:"rankChange"`
+}
+
+type GameState struct {
+	Scoreboard     []ScoreboardEntry
+	Question       string
+	QuestionNumber int
+	Deadline       time.Time
+}
+
+func (r *Room) DisplayToken() string {
+	return r.displayToken
+}
+
+func (r *Room) AddDisplay(display Display) GameState {
+	r.displays[display] = true
+	return r.State()
+}
+
+func (r *Room) State() GameState {
+	state := GameState{Scoreboard: r.scoreboard(false), QuestionNumber: r.nextQuestion}
+	if r.options.Mode == BlitzMode && r.blitzGameOver != nil {
+		state.Deadline = r.blitzDeadline
+	}
+	if r.currentQuestion != nil && !r.currentQuestion.finished {
+		state.Question = r.currentQuestion.Street.Name
+		state.QuestionNumber = r.currentQuestion.number
+		state.Deadline = r.currentQuestion.deadline
+	}
+	return state
+}
+
+func (r *Room) RemoveDisplay(display Display) {
+	delete(r.displays, display)
+}
+
+func (r *Room) notifyDisplays(consumer func(Display)) {
+	for display := range r.displays {
+		go consumer(display)
+	}
+}
+
+func (r *Room) notifyAnswerCount(answered int) {
+	total := len(r.players)
+	if r.currentQuestion != nil {
+		total = r.awaited(r.currentQuestion)
+	}
+	r.notifyDisplays(
+		func(display Display) {
+			display.NotifyAnswerCount(answered, total)
+		},
+	)
+}
+
+func (r *Room) notifyScoreboard() {
+	scoreboard := r.scoreboard(true)
+	r.notifyDisplays(
+		func(display Display) {
+			display.NotifyScoreboard(scoreboard)
+		},
+	)
+}
+
+func (r *Room) scoreboard(updateRanks bool) []ScoreboardEntry {
+	result := make([]ScoreboardEntry, 0, len(r.players))
+	for key, player := range r.players {
+		result = append(result, ScoreboardEntry{PlayerKey: key, Name: player.Name, Points: r.points[key]})
+	}
+	sort.Slice(
+		result, func(i, j int) bool {
+			if result[i].Points != result[j].Points {
+				return result[i].Points > result[j].Points
+			}
+			return result[i].Name < result[j].Name
+		},
+	)
+	for i := range result {
+		result[i].Rank = i + 1
+		if i > 0 && result[i].Points == result[i-1].Points {
+			result[i].Rank = result[i-1].Rank
+		}
+		if previous, ok := r.ranks[result[i].PlayerKey]; ok {
+			result[i].RankChange = previous - result[i].Rank
+		}
+	}
+	if updateRanks && r.ranks != nil {
+		for _, entry := range result {
+			r.ranks[entry.PlayerKey] = entry.Rank
+		}
+	}
+	return result
+}
@@ -0,0 +1,97 @@
+package contest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoom_scoreboard(t *testing.T) {
+	tests := []struct {
+		name       string
+		points     map[string]int
+		ranks      map[string]int
+		wantRanks  map[string]int
+		wantChange map[string]int
+	}{
+		{
+			name:      "distinct points",
+			points:    map[string]int{"Alice": 10, "Bob": 20, "Carol": 0},
+			wantRanks: map[string]int{"Bob": 1, "Alice": 2, "Carol": 3},
+		},
+		{
+			name:      "tied points",
+			points:    map[string]int{"Alice": 10, "Bob": 10, "Carol": 5},
+			wantRanks: map[string]int{"Alice": 1, "Bob": 1, "Carol": 3},
+		},
+		{
+			name:       "overtaking",
+			points:     map[string]int{"Alice": 30, "Bob": 20, "Carol": 20},
+			ranks:      map[string]int{"Alice": 3, "Bob": 1, "Carol": 2},
+			wantRanks:  map[string]int{"Alice": 1, "Bob": 2, "Carol": 2},
+			wantChange: map[string]int{"Alice": 2, "Bob": -1, "Carol": 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				room := newTestRoom(nil)
+				keys := make(map[string]string)
+				room.points = make(map[string]int)
+				room.ranks = make(map[string]int)
+				for name, points := range tt.points {
+					player, _ := joinConnected(room, name)
+					keys[player.Key] = name
+					room.points[player.Key] = points
+					if rank, ok := tt.ranks[name]; ok {
+						room.ranks[player.Key] = rank
+					}
+				}
+				scoreboard := room.scoreboard(true)
+				assert.Len(t, scoreboard, len(tt.points))
+				for _, entry := range scoreboard {
+					name := keys[entry.PlayerKey]
+					assert.Equal(t, name, entry.Name)
+					assert.Equal(t, tt.points[name], entry.Points)
+					assert.Equal(t, tt.wantRanks[name], entry.Rank, name)
+					assert.Equal(t, tt.wantChange[name], entry.RankChange, name)
+					assert.Equal(t, entry.Rank, room.ranks[entry.PlayerKey])
+				}
+			},
+		)
+	}
+}
+
+func TestRoom_AddDisplay(t *testing.T) {
+	t.Parallel()
+	room := newTestRoom(nil)
+	alice, _ := joinConnected(room, "Alice")
+	bob, _ := joinConnected(room, "Bob")
+	display := &testNotifier{}
+	room.Lock()
+	assert.NotEmpty(t, room.DisplayToken())
+	state := room.AddDisplay(display)
+	room.Unlock()
+	assert.Len(t, state.Scoreboard, 2)
+	assert.Empty(t, state.Question)
+	startGame(t, room, alice.Key)
+	answer(t, room, alice.Key, rightGuess)
+	display.await(t, "questionDeadline", 1)
+	counts := display.await(t, "answerCount", 1)
+	assert.Equal(t, [2]int{1, 2}, counts[0])
+	room.Lock()
+	state = room.AddDisplay(&testNotifier{})
+	room.Unlock()
+	assert.Equal(t, testStreet, state.Question)
+	assert.False(t, state.Deadline.IsZero())
+	answer(t, room, bob.Key, wrongGuess)
+	scoreboards := display.await(t, "scoreboard", 1)
+	scoreboard := scoreboards[0].([]ScoreboardEntry)
+	assert.Equal(t, alice.Key, scoreboard[0].PlayerKey)
+	assert.Greater(t, scoreboard[0].Points, 0)
+	display.await(t, "playerAnswered", 2)
+	room.Lock()
+	room.RemoveDisplay(display)
+	room.Unlock()
+	assert.NoError(t, room.Close())
+}
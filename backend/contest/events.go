@@ -0,0 +1,104 @@
+package contest
+
+import (
+	"sync"
+	"time"
+)
+
+const eventLogSize = 500
+
+type SequencedNotifier interface {
+	Notifier
+	WithSequence(sequence int) Notifier
+}
+
+type event struct {
+	sequence  int
+	recipient string
+	consumer  func(Player)
+}
+
+func (e event) play(notifier Notifier) {
+	e.consumer(Player{Notifier: notifier})
+}
+
+func (e event) deliver(notifier Notifier) {
+	if sequencedNotifier, ok := notifier.(SequencedNotifier); ok && e.sequence > 0 {
+		notifier = sequencedNotifier.WithSequence(e.sequence)
+	}
+	e.play(notifier)
+}
+
+type eventLog struct {
+	sync.Mutex
+	sequence int
+	events   []event
+	latest   time.Time
+}
+
+func (l *eventLog) append(recipient string, consumer func(Player)) event {
+	l.Lock()
+	defer l.Unlock()
+	l.sequence = l.sequence + 1
+	l.latest = time.Now()
+	recorded := event{sequence: l.sequence, recipient: recipient, consumer: consumer}
+	l.events = append(l.events, recorded)
+	if len(l.events) > eventLogSize {
+		l.events = l.events[len(l.events)-eventLogSize:]
+	}
+	return recorded
+}
+
+func (l *eventLog) since(sequence int) ([]event, bool) {
+	l.Lock()
+	defer l.Unlock()
+	if sequence > l.sequence {
+		return nil, false
+	}
+	if len(l.events) > 0 && sequence < l.events[0].sequence-1 {
+		return nil, false
+	}
+	result := make([]event, 0, l.sequence-sequence)
+	for _, e := range l.events {
+		if e.sequence > sequence {
+			result = append(result, e)
+		}
+	}
+	return result, true
+}
+
+func (l *eventLog) lastActivity() time.Time {
+	l.Lock()
+	defer l.Unlock()
+	return l.latest
+}
+
+func (l *eventLog) current() int {
+	l.Lock()
+	defer l.Unlock()
+	return l.sequence
+}
+
+func (r *Room) Sequence() int {
+	return r.events.current()
+}
+
+func (r *Room) CanReplay(since int) bool {
+	_, ok := r.events.since(since)
+	return ok
+}
+
+func (r *Room) Replay(playerKey string, since int) func(Notifier) {
+	events, _ := r.events.since(since)
+	missed := make([]event, 0, len(events))
+	for _, e := range events {
+		if e.recipient == "" || e.recipient == playerKey {
+			missed = append(missed, e)
+		}
+	}
+	return func(notifier Notifier) {
+		for _, e := range missed {
+			e.deliver(notifier)
+		}
+	}
+}
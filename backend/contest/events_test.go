@@ -0,0 +1,110 @@
+package contest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sequencingNotifier struct {
+	*testNotifier
+}
+
+func (n sequencingNotifier) WithSequence(sequence int) Notifier {
+	n.record("sequence", sequence)
+	return n.testNotifier
+}
+
+func TestRoom_Replay(t *testing.T) {
+	room := newTestRoom(nil)
+	alice, _ := joinConnected(room, "Alice")
+	bob, _ := joinConnected(room, "Bob")
+	room.Lock()
+	start := room.Sequence()
+	room.SetOptions(room.Options(), alice.Key)
+	afterUpdate := room.Sequence()
+	room.notifyPlayer(
+		bob.Key, func(player Player) {
+			player.NotifyGameStarted(bob.Key)
+		},
+	)
+	room.TransferHost(bob.Key)
+	end := room.Sequence()
+	room.Unlock()
+	tests := []struct {
+		name       string
+		playerKey  string
+		since      int
+		canReplay  bool
+		wantTopics []string
+	}{
+		{
+			name:       "everything for Alice",
+			playerKey:  alice.Key,
+			since:      start,
+			canReplay:  true,
+			wantTopics: []string{"roomUpdated", "hostChanged"},
+		},
+		{
+			name:       "everything for Bob",
+			playerKey:  bob.Key,
+			since:      start,
+			canReplay:  true,
+			wantTopics: []string{"roomUpdated", "gameStarted", "hostChanged"},
+		},
+		{
+			name:       "after the update",
+			playerKey:  bob.Key,
+			since:      afterUpdate,
+			canReplay:  true,
+			wantTopics: []string{"gameStarted", "hostChanged"},
+		},
+		{name: "nothing missed", playerKey: alice.Key, since: end, canReplay: true},
+		{name: "sequence from the future", playerKey: alice.Key, since: end + 1},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				room.Lock()
+				canReplay := room.CanReplay(tt.since)
+				replay := room.Replay(tt.playerKey, tt.since)
+				room.Unlock()
+				require.Equal(t, tt.canReplay, canReplay)
+				notifier := &testNotifier{}
+				replay(sequencingNotifier{notifier})
+				var topics []string
+				var sequences []any
+				for index, topic := range notifier.topics {
+					if topic == "sequence" {
+						sequences = append(sequences, notifier.payloads[index])
+						continue
+					}
+					topics = append(topics, topic)
+				}
+				assert.Equal(t, tt.wantTopics, topics)
+				assert.Len(t, sequences, len(topics))
+				for index := 1; index < len(sequences); index++ {
+					assert.Greater(t, sequences[index], sequences[index-1])
+				}
+			},
+		)
+	}
+}
+
+func TestRoom_ReplayTruncatedLog(t *testing.T) {
+	room := newTestRoom(nil)
+	alice, _ := joinConnected(room, "Alice")
+	room.Lock()
+	defer room.Unlock()
+	since := room.Sequence()
+	for index := 0; index <= eventLogSize; index++ {
+		room.notifyPlayer(
+			alice.Key, func(player Player) {
+				player.NotifyGameStarted(alice.Key)
+			},
+		)
+	}
+	assert.False(t, room.CanReplay(since))
+	assert.True(t, room.CanReplay(since+1))
+}
@@ -0,0 +1,103 @@
+package contest
+
+import (
+	"time"
+
+	"github.com/fafeitsch/city-knowledge-contest/backend/keygen"
+)
+
+const ghostReplayInterval = 100 * time.Millisecond
+
+type Ghost struct {
+	Key     string `json:"key"`
+	Name    string `json:"name"`
+	answers []AnswerRecord
+}
+
+func (r *Room) AddGhost(name string, answers []AnswerRecord) Ghost {
+	ghost := Ghost{Key: keygen.PlayerKey(), Name: name, answers: answers}
+	r.ghosts = append(r.ghosts, ghost)
+	return ghost
+}
+
+func (r *Room) Ghosts() []Ghost {
+	result := make([]Ghost, len(r.ghosts))
+	copy(result, r.ghosts)
+	return result
+}
+
+func (r *Room) replayGhosts(question *Question) {
+	if len(r.ghosts) == 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(ghostReplayInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+			case <-question.quit:
+				return
+			}
+			r.Lock()
+			if question.finished {
+				r.Unlock()
+				return
+			}
+			if !r.paused {
+				r.deliverGhostAnswers(question, question.elapsed(time.Now()))
+			}
+			if r.everyoneAnswered(question) {
+				question.finish()
+			}
+			r.Unlock()
+		}
+	}()
+}
+
+func (g Ghost) answer(number int) (AnswerRecord, bool) {
+	if number >= len(g.answers) {
+		return AnswerRecord{}, false
+	}
+	answer := g.answers[number]
+	return answer, answer.Answered && answer.Guess != nil
+}
+
+func (r *Room) ghostsAnswered(question *Question) bool {
+	for _, ghost := range r.ghosts {
+		answer, ok := ghost.answer(question.number)
+		if !ok || time.Duration(answer.ResponseTimeMs)*time.Millisecond > question.duration {
+			continue
+		}
+		if _, ok := question.points[ghost.Key]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *Room) deliverGhostAnswers(question *Question, elapsed time.Duration) {
+	for _, ghost := range r.ghosts {
+		answer, ok := ghost.answer(question.number)
+		if !ok {
+			continue
+		}
+		if _, ok := question.points[ghost.Key]; ok {
+			continue
+		}
+		responseTime := time.Duration(answer.ResponseTimeMs) * time.Millisecond
+		if responseTime > elapsed {
+			continue
+		}
+		key := ghost.Key
+		points := answer.Points
+		question.points[key] = points
+		question.answers[key] = *answer.Guess
+		question.responseTimes[key] = responseTime
+		r.notifyPlayers(
+			func(player Player) {
+				player.NotifyPlayerAnswered(key, points)
+			},
+		)
+	}
+}
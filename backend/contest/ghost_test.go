@@ -0,0 +1,104 @@
+package contest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fafeitsch/city-knowledge-contest/backend/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoom_deliverGhostAnswers(t *testing.T) {
+	guess := types.Coordinate{Lat: 49.79, Lng: 9.93}
+	tests := []struct {
+		name      string
+		answers   []AnswerRecord
+		delivered bool
+		elapsed   time.Duration
+		want      bool
+	}{
+		{
+			name:    "due answer",
+			answers: []AnswerRecord{{Answered: true, Guess: &guess, ResponseTimeMs: 2000, Points: 80}},
+			elapsed: 3 * time.Second,
+			want:    true,
+		},
+		{
+			name:    "answer not yet due",
+			answers: []AnswerRecord{{Answered: true, Guess: &guess, ResponseTimeMs: 2000, Points: 80}},
+			elapsed: time.Second,
+		},
+		{
+			name:    "unanswered question",
+			answers: []AnswerRecord{{ResponseTimeMs: 0}},
+			elapsed: 3 * time.Second,
+		},
+		{
+			name:    "question not recorded",
+			elapsed: 3 * time.Second,
+		},
+		{
+			name:      "already delivered",
+			answers:   []AnswerRecord{{Answered: true, Guess: &guess, ResponseTimeMs: 2000, Points: 80}},
+			delivered: true,
+			elapsed:   3 * time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				room := newTestRoom(nil)
+				_, notifier := joinConnected(room, "Alice")
+				ghost := room.AddGhost("Ghost", tt.answers)
+				question := &Question{
+					points:        make(map[string]int),
+					answers:       make(map[string]types.Coordinate),
+					responseTimes: make(map[string]time.Duration),
+				}
+				if tt.delivered {
+					question.points[ghost.Key] = 80
+				}
+				room.Lock()
+				room.deliverGhostAnswers(question, tt.elapsed)
+				room.Unlock()
+				_, answered := question.answers[ghost.Key]
+				assert.Equal(t, tt.want, answered)
+				if !tt.want {
+					return
+				}
+				assert.Equal(t, 80, question.points[ghost.Key])
+				assert.Equal(t, 2*time.Second, question.responseTimes[ghost.Key])
+				assert.Equal(t, []any{ghost.Key}, notifier.await(t, "playerAnswered", 1))
+			},
+		)
+	}
+}
+
+func TestRoom_replayGhosts(t *testing.T) {
+	t.Parallel()
+	room := newTestRoom(nil)
+	alice, notifier := joinConnected(room, "Alice")
+	guess := types.Coordinate{Lat: 49.79, Lng: 9.93}
+	room.Lock()
+	fast := room.AddGhost("Fast", []AnswerRecord{{Answered: true, Guess: &guess, ResponseTimeMs: 3300, Points: 90}})
+	slow := room.AddGhost("Slow", []AnswerRecord{{Answered: true, Guess: &guess, ResponseTimeMs: 4500, Points: 60}})
+	late := room.AddGhost("Late", []AnswerRecord{{Answered: true, Guess: &guess, ResponseTimeMs: 60000, Points: 10}})
+	assert.Len(t, room.Ghosts(), 3)
+	room.Unlock()
+	startGame(t, room, alice.Key)
+	awaitQuestion(t, room, alice.Key)
+	answered := notifier.await(t, "playerAnswered", 1)
+	assert.Equal(t, fast.Key, answered[0])
+	answer(t, room, alice.Key, rightGuess)
+	time.Sleep(3 * ghostReplayInterval)
+	assert.Zero(t, notifier.count("questionResults"))
+	result := notifier.await(t, "questionResults", 1)[0].(QuestionResult)
+	assert.Contains(t, result.Guesses, fast.Key)
+	assert.Contains(t, result.Guesses, slow.Key)
+	assert.NotContains(t, result.Guesses, late.Key)
+	assert.Equal(t, 90, result.PointDelta[fast.Key])
+	assert.Equal(t, 60, result.PointDelta[slow.Key])
+	require.Equal(t, []any{fast.Key, alice.Key, slow.Key}, notifier.all("playerAnswered"))
+	require.NoError(t, room.Close())
+}
@@ -0,0 +1,123 @@
+package contest
+
+import (
+	"sort"
+	"time"
+)
+
+const hostMigrationDelay = 10 * time.Second
+
+type Permission string
+
+const (
+	UpdateRoomPermission  Permission = "updateRoom"
+	StartGamePermission   Permission = "startGame"
+	AdvanceGamePermission Permission = "advanceGame"
+	PauseGamePermission   Permission = "pauseGame"
+	InvitePermission      Permission = "invite"
+)
+
+func IsPermission(name string) bool {
+	switch Permission(name) {
+	case UpdateRoomPermission, StartGamePermission, AdvanceGamePermission, PauseGamePermission, InvitePermission:
+		return true
+	}
+	return false
+}
+
+func (r *Room) Host() string {
+	return r.host
+}
+
+func (r *Room) IsHost(playerKey string) bool {
+	return r.host == playerKey
+}
+
+func (r *Room) CoHostPermissions(playerKey string) []Permission {
+	return r.coHosts[playerKey]
+}
+
+func (r *Room) HasPermission(playerKey string, permission Permission) bool {
+	if r.host == playerKey {
+		return true
+	}
+	for _, delegated := range r.coHosts[playerKey] {
+		if delegated == permission {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Room) DelegatePermissions(target string, permissions []Permission) {
+	if len(permissions) == 0 {
+		delete(r.coHosts, target)
+		return
+	}
+	r.coHosts[target] = permissions
+}
+
+func (r *Room) TransferHost(target string) {
+	delete(r.coHosts, target)
+	r.host = target
+	r.notifyHostChanged()
+}
+
+func (r *Room) migrateHost() {
+	candidates := make([]*Player, 0, len(r.players))
+	for key, player := range r.players {
+		if key != r.host {
+			candidates = append(candidates, player)
+		}
+	}
+	if len(candidates) == 0 {
+		r.host = ""
+		return
+	}
+	sort.Slice(
+		candidates, func(i, j int) bool {
+			a, b := candidates[i], candidates[j]
+			if (a.Notifier != nil) != (b.Notifier != nil) {
+				return a.Notifier != nil
+			}
+			if (len(r.coHosts[a.Key]) > 0) != (len(r.coHosts[b.Key]) > 0) {
+				return len(r.coHosts[a.Key]) > 0
+			}
+			return a.joined.Before(b.joined)
+		},
+	)
+	r.TransferHost(candidates[0].Key)
+}
+
+func (r *Room) awaitHost(player *Player) {
+	disconnected := player.disconnected
+	time.AfterFunc(
+		hostMigrationDelay, func() {
+			r.Lock()
+			defer r.Unlock()
+			r.migrateAbsentHost(player.Key, disconnected)
+		},
+	)
+}
+
+func (r *Room) migrateAbsentHost(playerKey string, disconnected time.Time) {
+	player, ok := r.players[playerKey]
+	if !ok || r.host != playerKey || player.connected || !player.disconnected.Equal(disconnected) {
+		return
+	}
+	for key, candidate := range r.players {
+		if key != playerKey && candidate.connected {
+			r.migrateHost()
+			return
+		}
+	}
+}
+
+func (r *Room) notifyHostChanged() {
+	host := r.players[r.host]
+	r.notifyPlayers(
+		func(player Player) {
+			player.NotifyHostChanged(host.Key, host.Name)
+		},
+	)
+}
@@ -0,0 +1,161 @@
+package contest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoom_HasPermission(t *testing.T) {
+	room := newTestRoom(nil)
+	host, _ := joinConnected(room, "Host")
+	coHost, _ := joinConnected(room, "CoHost")
+	player, _ := joinConnected(room, "Player")
+	room.DelegatePermissions(coHost.Key, []Permission{StartGamePermission, InvitePermission})
+	tests := []struct {
+		name       string
+		playerKey  string
+		permission Permission
+		want       bool
+	}{
+		{name: "host may update the room", playerKey: host.Key, permission: UpdateRoomPermission, want: true},
+		{name: "host may pause", playerKey: host.Key, permission: PauseGamePermission, want: true},
+		{name: "co-host may start", playerKey: coHost.Key, permission: StartGamePermission, want: true},
+		{name: "co-host may invite", playerKey: coHost.Key, permission: InvitePermission, want: true},
+		{name: "co-host may not update the room", playerKey: coHost.Key, permission: UpdateRoomPermission},
+		{name: "player may not start", playerKey: player.Key, permission: StartGamePermission},
+		{name: "unknown player may not start", playerKey: "unknown", permission: StartGamePermission},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				assert.Equal(t, tt.want, room.HasPermission(tt.playerKey, tt.permission))
+			},
+		)
+	}
+}
+
+func TestIsPermission(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{name: "updateRoom", want: true},
+		{name: "startGame", want: true},
+		{name: "advanceGame", want: true},
+		{name: "pauseGame", want: true},
+		{name: "invite", want: true},
+		{name: "kickPlayer"},
+		{name: "rule"},
+		{name: ""},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				assert.Equal(t, tt.want, IsPermission(tt.name))
+			},
+		)
+	}
+}
+
+func TestRoom_migrateHost(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		leave      bool
+		coHost     bool
+		wantHost   string
+		wantChange bool
+	}{
+		{name: "host leaves", leave: true, wantHost: "Second", wantChange: true},
+		{name: "host leaves with a co-host", leave: true, coHost: true, wantHost: "Third", wantChange: true},
+		{name: "host disconnects and comes back", wantHost: "Host"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(
+			tt.name, func(t *testing.T) {
+				t.Parallel()
+				room := newTestRoom(nil)
+				players := make(map[string]Player)
+				for _, name := range []string{"Host", "Second", "Third"} {
+					players[name], _ = joinConnected(room, name)
+				}
+				_, notifier := joinConnected(room, "Observer")
+				room.Lock()
+				if tt.coHost {
+					room.DelegatePermissions(players["Third"].Key, []Permission{StartGamePermission})
+				}
+				host, _ := room.FindPlayer(players["Host"].Key)
+				if tt.leave {
+					room.Leave(host.Key)
+				} else {
+					room.Disconnect(host.Key, host.Notifier)
+					assert.True(t, room.IsHost(host.Key))
+					room.Connect(host.Key, &testNotifier{}, "Host")
+				}
+				assert.Equal(t, players[tt.wantHost].Key, room.Host())
+				room.Unlock()
+				if tt.wantChange {
+					changes := notifier.await(t, "hostChanged", 1)
+					assert.Equal(t, players[tt.wantHost].Key, changes[0])
+				} else {
+					time.Sleep(50 * time.Millisecond)
+					assert.Zero(t, notifier.count("hostChanged"))
+				}
+			},
+		)
+	}
+}
+
+func TestRoom_migrateAbsentHost(t *testing.T) {
+	tests := []struct {
+		name            string
+		reconnect       bool
+		secondConnected bool
+		wantMigration   bool
+	}{
+		{name: "host stays away", secondConnected: true, wantMigration: true},
+		{name: "host comes back", reconnect: true, secondConnected: true},
+		{name: "nobody else is connected"},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				room := newTestRoom(nil)
+				host, _ := joinConnected(room, "Host")
+				second, _ := joinConnected(room, "Second")
+				room.Lock()
+				defer room.Unlock()
+				if !tt.secondConnected {
+					player, _ := room.FindPlayer(second.Key)
+					room.Disconnect(second.Key, player.Notifier)
+				}
+				player, _ := room.FindPlayer(host.Key)
+				room.Disconnect(host.Key, player.Notifier)
+				disconnected := player.disconnected
+				assert.Equal(t, host.Key, room.Host())
+				if tt.reconnect {
+					room.Connect(host.Key, &testNotifier{}, "Host")
+				}
+				room.migrateAbsentHost(host.Key, disconnected)
+				assert.Equal(t, tt.wantMigration, room.IsHost(second.Key))
+				assert.Equal(t, !tt.wantMigration, room.IsHost(host.Key))
+			},
+		)
+	}
+}
+
+func TestRoom_migrateHostWithoutCandidates(t *testing.T) {
+	room := newTestRoom(nil)
+	host, _ := joinConnected(room, "Host")
+	room.Lock()
+	room.Leave(host.Key)
+	assert.Empty(t, room.Host())
+	room.Unlock()
+	next, _ := joinConnected(room, "Next")
+	room.Lock()
+	defer room.Unlock()
+	assert.True(t, room.IsHost(next.Key))
+}
@@ -0,0 +1,142 @@
+package contest
+
+import (
+	"time"
+)
+
+const kickVoteTimeout = time.Minute
+
+const DefaultKickQuorum = 51
+
+type KickVote struct {
+	Target    string `json:"target"`
+	Initiator string `json:"initiator"`
+	Votes     int    `json:"votes"`
+	Needed    int    `json:"needed"`
+	Expired   bool   `json:"expired"`
+}
+
+type kickVote struct {
+	initiator string
+	voters    map[string]bool
+	expiry    *time.Timer
+}
+
+func (r *Room) HasKickVote(target string) bool {
+	_, ok := r.kickVotes[target]
+	return ok
+}
+
+func (r *Room) VoteKick(target string, voter string) bool {
+	player := r.players[target]
+	vote, ok := r.kickVotes[target]
+	if !ok {
+		vote = &kickVote{initiator: voter, voters: make(map[string]bool)}
+		vote.expiry = time.AfterFunc(
+			kickVoteTimeout, func() {
+				r.Lock()
+				defer r.Unlock()
+				r.expireKickVote(target, vote)
+			},
+		)
+		r.kickVotes[target] = vote
+	}
+	vote.voters[voter] = true
+	status := r.kickVoteStatus(target, vote)
+	player.receivedKicks = status.Votes
+	r.notifyPlayers(
+		func(p Player) {
+			if ok {
+				p.NotifyKickVoteUpdated(status)
+			} else {
+				p.NotifyKickVoteStarted(status)
+			}
+		},
+	)
+	if status.Votes < status.Needed {
+		return false
+	}
+	vote.expiry.Stop()
+	delete(r.kickVotes, target)
+	if player.Profile != "" {
+		r.bannedProfiles[player.Profile] = true
+	}
+	if player.address != "" {
+		r.bannedAddresses[player.address] = true
+	}
+	r.Kick(target, vote.initiator)
+	return true
+}
+
+func (r *Room) IsBanned(profileId string, address string) bool {
+	return (profileId != "" && r.bannedProfiles[profileId]) || r.bannedAddresses[address]
+}
+
+func (r *Room) expireKickVote(target string, vote *kickVote) {
+	if r.kickVotes[target] != vote {
+		return
+	}
+	delete(r.kickVotes, target)
+	if player, ok := r.players[target]; ok {
+		player.receivedKicks = 0
+	}
+	status := r.kickVoteStatus(target, vote)
+	status.Expired = true
+	r.notifyPlayers(
+		func(p Player) {
+			p.NotifyKickVoteUpdated(status)
+		},
+	)
+}
+
+func (r *Room) cancelKickVote(target string) {
+	if vote, ok := r.kickVotes[target]; ok {
+		vote.expiry.Stop()
+		delete(r.kickVotes, target)
+	}
+}
+
+func (r *Room) withdrawKickVotes(voter string) {
+	for target, vote := range r.kickVotes {
+		if !vote.voters[voter] {
+			continue
+		}
+		delete(vote.voters, voter)
+		if len(vote.voters) == 0 {
+			r.cancelKickVote(target)
+		}
+		status := r.kickVoteStatus(target, vote)
+		status.Expired = len(vote.voters) == 0
+		if player, ok := r.players[target]; ok {
+			player.receivedKicks = status.Votes
+		}
+		r.notifyPlayers(
+			func(p Player) {
+				p.NotifyKickVoteUpdated(status)
+			},
+		)
+	}
+}
+
+func (r *Room) kickVoteStatus(target string, vote *kickVote) KickVote {
+	electorate := 0
+	votes := 0
+	for key, player := range r.players {
+		if key != target && (player.Notifier != nil || vote.voters[key]) {
+			electorate = electorate + 1
+		}
+		if vote.voters[key] {
+			votes = votes + 1
+		}
+	}
+	needed := (electorate*r.options.KickQuorum + 99) / 100
+	if needed < 1 {
+		needed = 1
+	}
+	return KickVote{
+		Target:    target,
+		Initiator: vote.initiator,
+		Votes:     votes,
+		Needed:    needed,
+	}
+}
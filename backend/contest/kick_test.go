@@ -0,0 +1,117 @@
+package contest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoom_VoteKick(t *testing.T) {
+	tests := []struct {
+		name    string
+		players int
+		quorum  int
+		voters  int
+		needed  int
+		kicked  bool
+	}{
+		{name: "single vote of two electors", players: 3, quorum: 51, voters: 1, needed: 2},
+		{name: "majority of two electors", players: 3, quorum: 51, voters: 2, needed: 2, kicked: true},
+		{name: "half of two electors", players: 3, quorum: 50, voters: 1, needed: 1, kicked: true},
+		{name: "two of four electors", players: 5, quorum: 51, voters: 2, needed: 3},
+		{name: "three of four electors", players: 5, quorum: 51, voters: 3, needed: 3, kicked: true},
+		{name: "unanimity", players: 4, quorum: 100, voters: 3, needed: 3, kicked: true},
+		{name: "single elector", players: 2, quorum: 1, voters: 1, needed: 1, kicked: true},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				room := newTestRoom(
+					func(options *RoomOptions) {
+						options.KickQuorum = tt.quorum
+					},
+				)
+				players := make([]Player, 0, tt.players)
+				var notifier *testNotifier
+				for index := 0; index < tt.players; index++ {
+					player, playerNotifier := joinConnected(room, fmt.Sprintf("Player %d", index))
+					players = append(players, player)
+					if notifier == nil {
+						notifier = playerNotifier
+					}
+				}
+				target := players[len(players)-1]
+				room.Lock()
+				room.SetProfile(target.Key, "target-profile")
+				kicked := false
+				for _, voter := range players[:tt.voters] {
+					kicked = room.VoteKick(target.Key, voter.Key)
+				}
+				_, stillThere := room.FindPlayer(target.Key)
+				room.Unlock()
+				assert.Equal(t, tt.kicked, kicked)
+				assert.Equal(t, tt.kicked, !stillThere)
+				assert.Equal(t, tt.kicked, room.IsBanned("", target.Name))
+				assert.Equal(t, tt.kicked, room.IsBanned("target-profile", "another address"))
+				assert.False(t, room.IsBanned("", "another address"))
+				started := notifier.await(t, "kickVoteStarted", 1)[0].(KickVote)
+				assert.Equal(t, tt.needed, started.Needed)
+				assert.Equal(t, players[0].Key, started.Initiator)
+				if tt.voters > 1 {
+					notifier.await(t, "kickVoteUpdated", tt.voters-1)
+				}
+				if tt.kicked {
+					notifier.await(t, "playerKicked", 1)
+				}
+			},
+		)
+	}
+}
+
+func TestRoom_expireKickVote(t *testing.T) {
+	room := newTestRoom(nil)
+	alice, notifier := joinConnected(room, "Alice")
+	bob, _ := joinConnected(room, "Bob")
+	joinConnected(room, "Carol")
+	room.Lock()
+	require.False(t, room.VoteKick(bob.Key, alice.Key))
+	require.True(t, room.HasKickVote(bob.Key))
+	room.expireKickVote(bob.Key, room.kickVotes[bob.Key])
+	assert.False(t, room.HasKickVote(bob.Key))
+	room.Unlock()
+	updates := notifier.await(t, "kickVoteUpdated", 1)
+	assert.True(t, updates[0].(KickVote).Expired)
+	assert.False(t, room.IsBanned("", "Bob"))
+}
+
+func TestRoom_withdrawKickVotes(t *testing.T) {
+	room := newTestRoom(nil)
+	alice, _ := joinConnected(room, "Alice")
+	bob, _ := joinConnected(room, "Bob")
+	carol, _ := joinConnected(room, "Carol")
+	_, notifier := joinConnected(room, "Dave")
+	joinConnected(room, "Eve")
+	room.Lock()
+	require.False(t, room.VoteKick(bob.Key, alice.Key))
+	require.False(t, room.VoteKick(bob.Key, carol.Key))
+	room.Leave(carol.Key)
+	require.True(t, room.HasKickVote(bob.Key))
+	room.Unlock()
+	rejoined, _ := joinConnected(room, "Carol")
+	room.Lock()
+	require.False(t, room.VoteKick(bob.Key, rejoined.Key))
+	status := room.kickVoteStatus(bob.Key, room.kickVotes[bob.Key])
+	assert.Equal(t, 2, status.Votes)
+	assert.Equal(t, 3, status.Needed)
+	room.Leave(alice.Key)
+	room.Leave(rejoined.Key)
+	assert.False(t, room.HasKickVote(bob.Key))
+	player, _ := room.FindPlayer(bob.Key)
+	assert.Zero(t, player.receivedKicks)
+	room.Unlock()
+	updates := notifier.await(t, "kickVoteUpdated", 5)
+	assert.Contains(t, updates, KickVote{Target: bob.Key, Initiator: alice.Key, Votes: 1, Needed: 2})
+	assert.Contains(t, updates, KickVote{Target: bob.Key, Initiator: alice.Key, Needed: 2, Expired: true})
+}
@@ -0,0 +1,59 @@
+package contest
+
+type LateJoinScore string
+
+const (
+	ZeroLateJoinScore    LateJoinScore = "zero"
+	LowestLateJoinScore  LateJoinScore = "lowest"
+	AverageLateJoinScore LateJoinScore = "average"
+)
+
+func (r *Room) AcceptsPlayers() bool {
+	return !r.started || r.finished || !r.options.RefuseLateJoin
+}
+
+func (r *Room) admitLateJoiner(player *Player) {
+	player.firstQuestion = r.nextQuestion
+	if r.points == nil {
+		return
+	}
+	r.points[player.Key] = r.lateJoinScore(player.Key)
+	if r.options.Mode == BlitzMode && r.blitzGameOver != nil {
+		r.startBlitzStream(player.Key)
+	}
+}
+
+func (r *Room) lateJoinScore(playerKey string) int {
+	if r.options.LateJoinScore == ZeroLateJoinScore {
+		return 0
+	}
+	lowest := -1
+	sum := 0
+	count := 0
+	for key := range r.players {
+		if key == playerKey {
+			continue
+		}
+		points := r.points[key]
+		if lowest < 0 || points < lowest {
+			lowest = points
+		}
+		sum = sum + points
+		count = count + 1
+	}
+	if count == 0 {
+		return 0
+	}
+	if r.options.LateJoinScore == LowestLateJoinScore {
+		return lowest
+	}
+	return sum / count
+}
+
+func (r *Room) participates(playerKey string, question *Question) bool {
+	if question.player != "" {
+		return question.player == playerKey
+	}
+	player, ok := r.players[playerKey]
+	return ok && player.firstQuestion <= question.number
+}
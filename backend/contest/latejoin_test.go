@@ -0,0 +1,103 @@
+package contest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoom_lateJoinScore(t *testing.T) {
+	tests := []struct {
+		name   string
+		score  LateJoinScore
+		points []int
+		want   int
+	}{
+		{name: "zero", score: ZeroLateJoinScore, points: []int{30, 60}, want: 0},
+		{name: "lowest", score: LowestLateJoinScore, points: []int{30, 60, 45}, want: 30},
+		{name: "average", score: AverageLateJoinScore, points: []int{30, 60, 45}, want: 45},
+		{name: "average rounds down", score: AverageLateJoinScore, points: []int{30, 61}, want: 45},
+		{name: "lowest without other players", score: LowestLateJoinScore, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				room := newTestRoom(
+					func(options *RoomOptions) {
+						options.LateJoinScore = tt.score
+					},
+				)
+				room.points = make(map[string]int)
+				for _, points := range tt.points {
+					player, _ := joinConnected(room, "Player")
+					room.points[player.Key] = points
+				}
+				late, _ := joinConnected(room, "Late")
+				assert.Equal(t, tt.want, room.lateJoinScore(late.Key))
+			},
+		)
+	}
+}
+
+func TestRoom_AcceptsPlayers(t *testing.T) {
+	tests := []struct {
+		name     string
+		started  bool
+		finished bool
+		refuse   bool
+		want     bool
+	}{
+		{name: "waiting", want: true},
+		{name: "waiting and refusing late joins", refuse: true, want: true},
+		{name: "running", started: true, want: true},
+		{name: "running and refusing late joins", started: true, refuse: true},
+		{name: "finished and refusing late joins", started: true, finished: true, refuse: true, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				room := newTestRoom(
+					func(options *RoomOptions) {
+						options.RefuseLateJoin = tt.refuse
+					},
+				)
+				room.started = tt.started
+				room.finished = tt.finished
+				assert.Equal(t, tt.want, room.AcceptsPlayers())
+			},
+		)
+	}
+}
+
+func TestRoom_JoinRunningGame(t *testing.T) {
+	t.Parallel()
+	room := newTestRoom(
+		func(options *RoomOptions) {
+			options.NumberOfQuestions = 2
+			options.LateJoinScore = LowestLateJoinScore
+		},
+	)
+	alice, notifier := joinConnected(room, "Alice")
+	bob, _ := joinConnected(room, "Bob")
+	startGame(t, room, alice.Key)
+	awaitQuestion(t, room, alice.Key)
+	late, _ := joinConnected(room, "Late")
+	room.Lock()
+	require.False(t, room.HasActiveQuestion(late.Key))
+	room.Unlock()
+	answer(t, room, alice.Key, rightGuess)
+	answer(t, room, bob.Key, wrongGuess)
+	first := notifier.await(t, "questionResults", 1)[0].(QuestionResult)
+	assert.NotContains(t, first.PointDelta, late.Key)
+	awaitAdvance(t, room)
+	answer(t, room, late.Key, rightGuess)
+	answer(t, room, alice.Key, wrongGuess)
+	answer(t, room, bob.Key, wrongGuess)
+	awaitAdvance(t, room)
+	ended := notifier.awaitEnd(t)
+	second := notifier.all("questionResults")[1].(QuestionResult)
+	assert.Equal(t, second.PointDelta[late.Key], ended.points[late.Key])
+	assert.Greater(t, ended.points[late.Key], 0)
+	assert.Equal(t, first.PointDelta[alice.Key], ended.points[alice.Key])
+}
@@ -0,0 +1,37 @@
+package contest
+
+type RoomStatus string
+
+const (
+	WaitingStatus  RoomStatus = "waiting"
+	RunningStatus  RoomStatus = "running"
+	PausedStatus   RoomStatus = "paused"
+	FinishedStatus RoomStatus = "finished"
+)
+
+func (r *Room) Status() RoomStatus {
+	if r.finished {
+		return FinishedStatus
+	}
+	if r.paused {
+		return PausedStatus
+	}
+	if r.started {
+		return RunningStatus
+	}
+	return WaitingStatus
+}
+
+func (r *Room) Name() string {
+	if r.options.Name != "" {
+		return r.options.Name
+	}
+	if host, ok := r.players[r.host]; ok {
+		return host.Name
+	}
+	return r.key
+}
+
+func (r *Room) Protected() bool {
+	return r.HasPassword() || r.inviteOnly
+}
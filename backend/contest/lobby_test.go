@@ -0,0 +1,88 @@
+package contest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoom_Status(t *testing.T) {
+	tests := []struct {
+		name     string
+		started  bool
+		paused   bool
+		finished bool
+		want     RoomStatus
+	}{
+		{name: "waiting", want: WaitingStatus},
+		{name: "running", started: true, want: RunningStatus},
+		{name: "paused", started: true, paused: true, want: PausedStatus},
+		{name: "finished", started: true, finished: true, want: FinishedStatus},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				room := newTestRoom(nil)
+				room.started = tt.started
+				room.paused = tt.paused
+				room.finished = tt.finished
+				assert.Equal(t, tt.want, room.Status())
+			},
+		)
+	}
+}
+
+func TestRoom_Name(t *testing.T) {
+	tests := []struct {
+		name     string
+		roomName string
+		host     string
+		want     string
+	}{
+		{name: "configured name", roomName: "Trivia Night", host: "Alice", want: "Trivia Night"},
+		{name: "host name", host: "Alice", want: "Alice"},
+		{name: "room key"},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				room := newTestRoom(
+					func(options *RoomOptions) {
+						options.Name = tt.roomName
+					},
+				)
+				if tt.host != "" {
+					joinConnected(room, tt.host)
+				}
+				want := tt.want
+				if want == "" {
+					want = room.Key()
+				}
+				assert.Equal(t, want, room.Name())
+			},
+		)
+	}
+}
+
+func TestRoom_Protected(t *testing.T) {
+	tests := []struct {
+		name       string
+		password   string
+		inviteOnly bool
+		want       bool
+	}{
+		{name: "open"},
+		{name: "password", password: "secret", want: true},
+		{name: "invite only", inviteOnly: true, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				room := newTestRoom(nil)
+				room.SetPassword(tt.password)
+				room.SetInviteOnly(tt.inviteOnly)
+				assert.Equal(t, tt.want, room.Protected())
+			},
+		)
+	}
+}
@@ -0,0 +1,75 @@
+package contest
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/fafeitsch/city-knowledge-contest/backend/geodata"
+	"github.com/fafeitsch/city-knowledge-contest/backend/types"
+)
+
+type provisionalGuess struct {
+	coordinate types.Coordinate
+	elapsed    time.Duration
+	locked     bool
+}
+
+func (r *Room) SubmitGuess(playerKey string, guess types.Coordinate, lock bool) {
+	_, ok := r.players[playerKey]
+	if !ok {
+		panic(fmt.Sprintf("player with key \"%s\" not found in this room", playerKey))
+	}
+	question := r.questionFor(playerKey)
+	question.guesses[playerKey] = &provisionalGuess{
+		coordinate: guess,
+		elapsed:    question.elapsed(time.Now()),
+		locked:     lock,
+	}
+	question.attempts[playerKey] = question.attempts[playerKey] + 1
+	r.notifyPlayers(
+		func(player Player) {
+			player.NotifyPlayerGuessed(playerKey, lock)
+		},
+	)
+	locked := 0
+	for _, guess := range question.guesses {
+		if guess.locked {
+			locked = locked + 1
+		}
+	}
+	r.notifyAnswerCount(locked)
+	if r.everyoneAnswered(question) {
+		question.finish()
+	}
+}
+
+func (r *Room) evaluateGuesses(question *Question) {
+	r.Lock()
+	guesses := make(map[string]provisionalGuess, len(question.guesses))
+	for key, guess := range question.guesses {
+		guesses[key] = *guess
+	}
+	r.Unlock()
+	points := make(map[string]int, len(guesses))
+	for key, guess := range guesses {
+		correct, err := geodata.VerifyAnswer(guess.coordinate, question.Street.Name)
+		if err != nil {
+			log.Printf("could not validate guess of player \"%s\" in room \"%s\": %v", key, r.key, err)
+		}
+		points[key] = 0
+		if correct {
+			points[key] = question.score(guess.elapsed)
+		}
+	}
+	r.Lock()
+	defer r.Unlock()
+	for key, value := range points {
+		question.points[key] = value
+		question.answers[key] = guesses[key].coordinate
+		question.responseTimes[key] = guesses[key].elapsed
+		if r.options.Mode == CoopMode && value > 0 && value > question.points[question.solvedBy] {
+			question.solvedBy = key
+		}
+	}
+}
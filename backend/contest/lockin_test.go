@@ -0,0 +1,54 @@
+package contest
+
+import (
+	"testing"
+
+	"github.com/fafeitsch/city-knowledge-contest/backend/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoom_SubmitGuess(t *testing.T) {
+	t.Parallel()
+	type guess struct {
+		coordinate types.Coordinate
+		lock       bool
+	}
+	tests := []struct {
+		name    string
+		guesses []guess
+		scored  bool
+	}{
+		{name: "locked right guess", guesses: []guess{{rightGuess, true}}, scored: true},
+		{name: "moved to the right street", guesses: []guess{{wrongGuess, false}, {rightGuess, true}}, scored: true},
+		{name: "moved away from the right street", guesses: []guess{{rightGuess, false}, {wrongGuess, true}}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(
+			tt.name, func(t *testing.T) {
+				t.Parallel()
+				room := newTestRoom(
+					func(options *RoomOptions) {
+						options.LockIn = true
+					},
+				)
+				alice, notifier := joinConnected(room, "Alice")
+				startGame(t, room, alice.Key)
+				for _, guess := range tt.guesses {
+					awaitQuestion(t, room, alice.Key)
+					room.Lock()
+					room.SubmitGuess(alice.Key, guess.coordinate, guess.lock)
+					room.Unlock()
+				}
+				result := notifier.await(t, "questionResults", 1)[0].(QuestionResult)
+				awaitAdvance(t, room)
+				ended := notifier.awaitEnd(t)
+				assert.Equal(t, tt.scored, result.PointDelta[alice.Key] > 0)
+				assert.Equal(t, tt.guesses[len(tt.guesses)-1].coordinate, result.Guesses[alice.Key])
+				assert.Equal(t, result.PointDelta[alice.Key], ended.points[alice.Key])
+				assert.Equal(t, len(tt.guesses), notifier.count("playerGuessed"))
+				assert.Equal(t, len(tt.guesses), ended.summary.Questions[0].Answers[0].Attempts)
+			},
+		)
+	}
+}
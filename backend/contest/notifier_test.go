@@ -0,0 +1,284 @@
+package contest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fafeitsch/city-knowledge-contest/backend/geodata"
+	"github.com/fafeitsch/city-knowledge-contest/backend/types"
+	"github.com/stretchr/testify/require"
+)
+
+const testStreet = "Main Street"
+
+var rightGuess = types.Coordinate{Lat: 49.79, Lng: 9.93}
+var wrongGuess = types.Coordinate{Lat: 1, Lng: 1}
+
+func TestMain(m *testing.M) {
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(writer http.ResponseWriter, request *http.Request) {
+				if strings.HasPrefix(request.URL.Path, "/search") {
+					_, _ = writer.Write([]byte(`[{"lat":"49.79","lon":"9.93"}]`))
+					return
+				}
+				road := "Elsewhere"
+				if strings.HasPrefix(request.URL.Query().Get("lat"), "49.79") {
+					road = testStreet
+				}
+				_ = json.NewEncoder(writer).Encode(map[string]any{"address": map[string]string{"road": road}})
+			},
+		),
+	)
+	geodata.NominatimServer = server.URL
+	code := m.Run()
+	server.Close()
+	os.Exit(code)
+}
+
+type gameEnded struct {
+	reason  string
+	points  map[string]int
+	summary GameSummary
+}
+
+type testNotifier struct {
+	mutex    sync.Mutex
+	topics   []string
+	payloads []any
+}
+
+func (n *testNotifier) record(topic string, payload any) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.topics = append(n.topics, topic)
+	n.payloads = append(n.payloads, payload)
+}
+
+func (n *testNotifier) all(topic string) []any {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	result := make([]any, 0)
+	for index, recorded := range n.topics {
+		if recorded == topic {
+			result = append(result, n.payloads[index])
+		}
+	}
+	return result
+}
+
+func (n *testNotifier) count(topic string) int {
+	return len(n.all(topic))
+}
+
+func (n *testNotifier) await(t *testing.T, topic string, count int) []any {
+	t.Helper()
+	require.Eventually(
+		t, func() bool {
+			return n.count(topic) >= count
+		}, 10*time.Second, 10*time.Millisecond, "no %d. event \"%s\" received", count, topic,
+	)
+	return n.all(topic)
+}
+
+func (n *testNotifier) awaitEnd(t *testing.T) gameEnded {
+	t.Helper()
+	events := n.await(t, "gameEnded", 1)
+	return events[len(events)-1].(gameEnded)
+}
+
+func (n *testNotifier) NotifyPlayerJoined(name string, playerKey string) {
+	n.record("playerJoined", playerKey)
+}
+
+func (n *testNotifier) NotifyPlayerLeft(name string, playerKey string) {
+	n.record("playerLeft", playerKey)
+}
+
+func (n *testNotifier) NotifyRoomUpdated(options RoomOptions, playerName string) {
+	n.record("roomUpdated", options)
+}
+
+func (n *testNotifier) NotifyGameStarted(playerKey string) {
+	n.record("gameStarted", playerKey)
+}
+
+func (n *testNotifier) NotifyGameDeadline(remaining time.Duration) {
+	n.record("gameDeadline", remaining)
+}
+
+func (n *testNotifier) NotifyGamePaused(playerKey string) {
+	n.record("gamePaused", playerKey)
+}
+
+func (n *testNotifier) NotifyGameResumed(playerKey string) {
+	n.record("gameResumed", playerKey)
+}
+
+func (n *testNotifier) NotifyPlayerAnswered(playerKey string, points int) {
+	n.record("playerAnswered", playerKey)
+}
+
+func (n *testNotifier) NotifyPlayerGuessed(playerKey string, locked bool) {
+	n.record("playerGuessed", locked)
+}
+
+func (n *testNotifier) NotifyQuestionCountdown(followUps int, question int) {
+	n.record("questionCountdown", followUps)
+}
+
+func (n *testNotifier) NotifyQuestion(street string, question int) {
+	n.record("question", question)
+}
+
+func (n *testNotifier) NotifyAnswerTimeCountdown(followUps int) {
+	n.record("answerTimeCountdown", followUps)
+}
+
+func (n *testNotifier) NotifyQuestionResults(result QuestionResult) {
+	n.record("questionResults", result)
+}
+
+func (n *testNotifier) NotifyNextQuestionIn(remaining time.Duration) {
+	n.record("nextQuestionIn", remaining)
+}
+
+func (n *testNotifier) NotifyPlayerReady(playerKey string) {
+	n.record("playerReady", playerKey)
+}
+
+func (n *testNotifier) NotifyGameEnded(reason string, points map[string]int, summary GameSummary) {
+	n.record("gameEnded", gameEnded{reason: reason, points: points, summary: summary})
+}
+
+func (n *testNotifier) NotifyPlayerKicked(playerKey string, name string, initiator string) {
+	n.record("playerKicked", playerKey)
+}
+
+func (n *testNotifier) NotifyHostChanged(playerKey string, name string) {
+	n.record("hostChanged", playerKey)
+}
+
+func (n *testNotifier) NotifyKickVoteStarted(vote KickVote) {
+	n.record("kickVoteStarted", vote)
+}
+
+func (n *testNotifier) NotifyKickVoteUpdated(vote KickVote) {
+	n.record("kickVoteUpdated", vote)
+}
+
+func (n *testNotifier) NotifyPlayerPresenceChanged(playerKey string, connected bool) {
+	n.record("playerPresenceChanged", connected)
+}
+
+func (n *testNotifier) NotifyRematch(playerKey string, game int, points map[string]int) {
+	n.record("rematch", points)
+}
+
+func (n *testNotifier) NotifyChallengeBeaten(result ChallengeResult) {
+	n.record("challengeBeaten", result)
+}
+
+func (n *testNotifier) NotifyDailyStanding(standing DailyStanding) {
+	n.record("dailyStanding", standing)
+}
+
+func (n *testNotifier) NotifyTournamentStage(stage TournamentStage) {
+	n.record("tournamentStage", stage)
+}
+
+func (n *testNotifier) NotifyTournamentStandings(standings []TournamentStanding) {
+	n.record("tournamentStandings", standings)
+}
+
+func (n *testNotifier) NotifyQuestionDeadline(questionNumber int, deadline time.Time) {
+	n.record("questionDeadline", deadline)
+}
+
+func (n *testNotifier) NotifyAnswerCount(answered int, total int) {
+	n.record("answerCount", [2]int{answered, total})
+}
+
+func (n *testNotifier) NotifyScoreboard(scoreboard []ScoreboardEntry) {
+	n.record("scoreboard", scoreboard)
+}
+
+func testStreets(count int) []geodata.Street {
+	result := make([]geodata.Street, 0, count)
+	for index := 0; index < count; index++ {
+		coordinate := rightGuess
+		result = append(result, geodata.Street{Name: testStreet, Coordinate: &coordinate})
+	}
+	return result
+}
+
+func newTestRoom(configure func(options *RoomOptions)) *Room {
+	room := NewRoom("")
+	room.options.StreetList = &geodata.StreetList{FileName: "test.json", Name: "Test", Streets: []string{testStreet}}
+	room.options.NumberOfQuestions = 1
+	room.options.MaxAnswerTime = 10 * time.Second
+	if configure != nil {
+		configure(&room.options)
+	}
+	room.SetStreets(testStreets(room.options.NumberOfQuestions))
+	return room
+}
+
+func joinConnected(room *Room, name string) (Player, *testNotifier) {
+	notifier := &testNotifier{}
+	room.Lock()
+	defer room.Unlock()
+	player := room.Join(name, name)
+	room.Connect(player.Key, notifier, name)
+	return player, notifier
+}
+
+func startGame(t *testing.T, room *Room, playerKey string) {
+	t.Helper()
+	room.Lock()
+	defer room.Unlock()
+	require.Empty(t, room.ConfigErrors())
+	room.Play(playerKey)
+}
+
+func answer(t *testing.T, room *Room, playerKey string, guess types.Coordinate) int {
+	t.Helper()
+	awaitQuestion(t, room, playerKey)
+	room.Lock()
+	defer room.Unlock()
+	points, err := room.AnswerQuestion(playerKey, guess)
+	require.NoError(t, err)
+	return points
+}
+
+func awaitQuestion(t *testing.T, room *Room, playerKey string) {
+	t.Helper()
+	require.Eventually(
+		t, func() bool {
+			room.Lock()
+			defer room.Unlock()
+			return room.HasActiveQuestion(playerKey)
+		}, 10*time.Second, 10*time.Millisecond, "player \"%s\" got no question", playerKey,
+	)
+}
+
+func awaitAdvance(t *testing.T, room *Room) {
+	t.Helper()
+	require.Eventually(
+		t, func() bool {
+			room.Lock()
+			defer room.Unlock()
+			if !room.CanBeAdvanced() {
+				return false
+			}
+			room.AdvanceToNextQuestion()
+			return true
+		}, 10*time.Second, 10*time.Millisecond, "game could not be advanced",
+	)
+}
@@ -0,0 +1,92 @@
+package contest
+
+import (
+	"time"
+)
+
+func (r *Room) CanBePaused() bool {
+	return r.started && !r.finished && !r.paused
+}
+
+func (r *Room) Paused() bool {
+	return r.paused
+}
+
+func (r *Room) PausedSince() time.Time {
+	return r.pausedSince
+}
+
+func (r *Room) Pause(playerKey string) {
+	r.paused = true
+	r.pausedSince = time.Now()
+	r.resume = make(chan bool)
+	for _, question := range r.activeQuestions() {
+		r.pauseIfNeeded(question)
+	}
+	if r.blitzPause != nil {
+		signalPause(r.blitzPause, r.resume)
+	}
+	r.notifyPlayers(
+		func(player Player) {
+			player.NotifyGamePaused(playerKey)
+		},
+	)
+}
+
+func (r *Room) Resume(playerKey string) {
+	for _, question := range r.activeQuestions() {
+		since := r.pausedSince
+		if question.begin.After(since) {
+			since = question.begin
+		}
+		question.paused = question.paused + time.Since(since)
+	}
+	r.paused = false
+	close(r.resume)
+	r.notifyPlayers(
+		func(player Player) {
+			player.NotifyGameResumed(playerKey)
+		},
+	)
+}
+
+func (r *Room) awaitResume() bool {
+	r.Lock()
+	paused := r.paused
+	resume := r.resume
+	r.Unlock()
+	if !paused {
+		return true
+	}
+	select {
+	case <-resume:
+		return true
+	case <-r.quit:
+		return false
+	}
+}
+
+func (r *Room) pauseIfNeeded(question *Question) {
+	if r.paused {
+		signalPause(question.pause, r.resume)
+	}
+}
+
+func signalPause(pause chan chan bool, resume chan bool) {
+	select {
+	case <-pause:
+	default:
+	}
+	pause <- resume
+}
+
+func (r *Room) activeQuestions() []*Question {
+	result := make([]*Question, 0, len(r.blitzQuestions)+1)
+	if r.currentQuestion != nil {
+		result = append(result, r.currentQuestion)
+	}
+	for _, question := range r.blitzQuestions {
+		result = append(result, question)
+	}
+	return result
+}
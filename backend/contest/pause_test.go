@@ -0,0 +1,63 @@
+package contest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoom_Pause(t *testing.T) {
+	t.Parallel()
+	room := newTestRoom(nil)
+	alice, notifier := joinConnected(room, "Alice")
+	room.Lock()
+	assert.False(t, room.CanBePaused())
+	room.Unlock()
+	startGame(t, room, alice.Key)
+	awaitQuestion(t, room, alice.Key)
+
+	room.Lock()
+	assert.True(t, room.CanBePaused())
+	room.Pause(alice.Key)
+	assert.False(t, room.CanBePaused())
+	assert.True(t, room.Paused())
+	room.Unlock()
+	time.Sleep(2 * time.Second)
+	room.Lock()
+	room.Resume(alice.Key)
+	assert.False(t, room.Paused())
+	room.Unlock()
+
+	points := answer(t, room, alice.Key, rightGuess)
+	assert.GreaterOrEqual(t, points, 90, "the paused time must not count as answer time")
+	awaitAdvance(t, room)
+	ended := notifier.awaitEnd(t)
+	assert.Equal(t, points, ended.points[alice.Key])
+	assert.Equal(t, 1, notifier.count("gamePaused"))
+	assert.Equal(t, 1, notifier.count("gameResumed"))
+}
+
+func TestRoom_PauseDuringCountdown(t *testing.T) {
+	t.Parallel()
+	room := newTestRoom(nil)
+	alice, notifier := joinConnected(room, "Alice")
+	startGame(t, room, alice.Key)
+	notifier.await(t, "questionCountdown", 1)
+	room.Lock()
+	room.Pause(alice.Key)
+	room.Unlock()
+	time.Sleep(4 * time.Second)
+	assert.Zero(t, notifier.count("question"))
+	assert.LessOrEqual(t, notifier.count("questionCountdown"), 2)
+	room.Lock()
+	room.Resume(alice.Key)
+	room.Unlock()
+	notifier.await(t, "question", 1)
+	assert.Equal(t, []any{2, 1, 0}, notifier.all("questionCountdown"))
+	answer(t, room, alice.Key, rightGuess)
+	awaitAdvance(t, room)
+	ended := notifier.awaitEnd(t)
+	responseTime := time.Duration(ended.summary.Questions[0].Answers[0].ResponseTimeMs) * time.Millisecond
+	assert.Less(t, responseTime, 4*time.Second)
+}
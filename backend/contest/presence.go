@@ -0,0 +1,99 @@
+package contest
+
+import "time"
+
+const DefaultGracePeriod = 2 * time.Minute
+
+func (p Player) Connected() bool {
+	return p.connected
+}
+
+func (r *Room) Heartbeat(playerKey string, notifier Notifier) {
+	player, ok := r.players[playerKey]
+	if !ok || player.Notifier != notifier {
+		return
+	}
+	r.setPresence(player, true)
+}
+
+func (r *Room) setPresence(player *Player, connected bool) {
+	if player.connected == connected {
+		return
+	}
+	player.connected = connected
+	r.notifyPlayers(
+		func(p Player) {
+			p.NotifyPlayerPresenceChanged(player.Key, connected)
+		},
+	)
+	if connected {
+		return
+	}
+	r.awaitPresence(player)
+	if r.host == player.Key {
+		r.awaitHost(player)
+	}
+	r.finishIfEveryoneAnswered()
+}
+
+func (r *Room) awaitPresence(player *Player) {
+	player.disconnected = time.Now()
+	disconnected := player.disconnected
+	time.AfterFunc(
+		r.options.GracePeriod, func() {
+			r.Lock()
+			defer r.Unlock()
+			r.removeAbsentPlayer(player.Key, disconnected)
+		},
+	)
+}
+
+func (r *Room) removeAbsentPlayer(playerKey string, disconnected time.Time) {
+	player, ok := r.players[playerKey]
+	if !ok || player.connected || !player.disconnected.Equal(disconnected) {
+		return
+	}
+	r.Leave(playerKey)
+}
+
+func (r *Room) awaits(playerKey string, question *Question) bool {
+	return r.players[playerKey].connected && r.participates(playerKey, question)
+}
+
+func (r *Room) awaited(question *Question) int {
+	result := 0
+	for key := range r.players {
+		if r.awaits(key, question) {
+			result = result + 1
+		}
+	}
+	return result
+}
+
+func (r *Room) everyoneAnswered(question *Question) bool {
+	for key := range r.players {
+		if !r.awaits(key, question) {
+			continue
+		}
+		if r.options.LockIn {
+			if guess, ok := question.guesses[key]; !ok || !guess.locked {
+				return false
+			}
+			continue
+		}
+		if _, ok := question.points[key]; !ok {
+			return false
+		}
+	}
+	return r.ghostsAnswered(question)
+}
+
+func (r *Room) finishIfEveryoneAnswered() {
+	question := r.currentQuestion
+	if question == nil || question.finished {
+		return
+	}
+	if r.everyoneAnswered(question) {
+		question.finish()
+	}
+}
@@ -0,0 +1,92 @@
+package contest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoom_awaitPresence(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name             string
+		connect          bool
+		disconnectBefore bool
+		disconnectAfter  bool
+		wantEarlyResults bool
+		wantRemoved      bool
+	}{
+		{name: "never connected", wantEarlyResults: true, wantRemoved: true},
+		{name: "disconnected before the answer", connect: true, disconnectBefore: true, wantEarlyResults: true, wantRemoved: true},
+		{name: "disconnected after the answer", connect: true, disconnectAfter: true, wantEarlyResults: true, wantRemoved: true},
+		{name: "connected", connect: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(
+			tt.name, func(t *testing.T) {
+				t.Parallel()
+				room := newTestRoom(nil)
+				alice, notifier := joinConnected(room, "Alice")
+				room.Lock()
+				room.options.GracePeriod = time.Second
+				bob := room.Join("Bob", "Bob")
+				bobNotifier := &testNotifier{}
+				if tt.connect {
+					room.Connect(bob.Key, bobNotifier, "Bob")
+				}
+				room.options.GracePeriod = DefaultGracePeriod
+				room.Unlock()
+				startGame(t, room, alice.Key)
+				room.Lock()
+				room.options.GracePeriod = time.Second
+				if tt.disconnectBefore {
+					room.Disconnect(bob.Key, bobNotifier)
+				}
+				room.Unlock()
+				answer(t, room, alice.Key, rightGuess)
+				if tt.disconnectAfter {
+					time.Sleep(200 * time.Millisecond)
+					assert.Zero(t, notifier.count("questionResults"))
+					room.Lock()
+					room.Disconnect(bob.Key, bobNotifier)
+					room.Unlock()
+				}
+				if !tt.wantEarlyResults {
+					time.Sleep(200 * time.Millisecond)
+					assert.Zero(t, notifier.count("questionResults"))
+					answer(t, room, bob.Key, wrongGuess)
+				}
+				result := notifier.await(t, "questionResults", 1)[0].(QuestionResult)
+				assert.Equal(t, !tt.wantEarlyResults, len(result.Guesses) == 2)
+				if tt.wantRemoved {
+					notifier.await(t, "playerLeft", 1)
+				}
+				room.Lock()
+				_, stillThere := room.FindPlayer(bob.Key)
+				room.Unlock()
+				assert.Equal(t, tt.wantRemoved, !stillThere)
+				require.NoError(t, room.Close())
+			},
+		)
+	}
+}
+
+func TestRoom_Heartbeat(t *testing.T) {
+	room := newTestRoom(nil)
+	alice, notifier := joinConnected(room, "Alice")
+	room.Lock()
+	player, _ := room.FindPlayer(alice.Key)
+	room.Heartbeat(alice.Key, &testNotifier{})
+	room.setPresence(player, false)
+	assert.False(t, player.Connected())
+	room.Heartbeat(alice.Key, &testNotifier{})
+	assert.False(t, player.Connected())
+	room.Heartbeat(alice.Key, notifier)
+	assert.True(t, player.Connected())
+	room.Unlock()
+	changes := notifier.await(t, "playerPresenceChanged", 3)
+	assert.ElementsMatch(t, []any{true, false, true}, changes)
+}
@@ -0,0 +1,21 @@
+package contest
+
+func (r *Room) SetProfile(playerKey string, profile string) {
+	player, ok := r.players[playerKey]
+	if !ok {
+		return
+	}
+	player.Profile = profile
+}
+
+func (r *Room) PlayerForProfile(profile string) (Player, bool) {
+	if profile == "" {
+		return Player{}, false
+	}
+	for _, player := range r.players {
+		if player.Profile == profile {
+			return *player, true
+		}
+	}
+	return Player{}, false
+}
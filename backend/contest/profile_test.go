@@ -0,0 +1,35 @@
+package contest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoom_PlayerForProfile(t *testing.T) {
+	room := newTestRoom(nil)
+	alice, _ := joinConnected(room, "Alice")
+	bob, _ := joinConnected(room, "Bob")
+	room.SetProfile(alice.Key, "alice-profile")
+	room.SetProfile("unknown", "ghost-profile")
+	tests := []struct {
+		name    string
+		profile string
+		wantKey string
+	}{
+		{name: "linked profile", profile: "alice-profile", wantKey: alice.Key},
+		{name: "anonymous player", profile: ""},
+		{name: "unknown player", profile: "ghost-profile"},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				player, ok := room.PlayerForProfile(tt.profile)
+				assert.Equal(t, tt.wantKey != "", ok)
+				assert.Equal(t, tt.wantKey, player.Key)
+			},
+		)
+	}
+	found, _ := room.FindPlayer(bob.Key)
+	assert.Empty(t, found.Profile)
+}
@@ -0,0 +1,41 @@
+package contest
+
+type RatingChange struct {
+	ProfileId string `json:"profileId"`
+	Before    int    `json:"before"`
+	After     int    `json:"after"`
+	Delta     int    `json:"delta"`
+	Games     int    `json:"games"`
+}
+
+type Rater interface {
+	Rate(streetList string, points map[string]int) map[string]RatingChange
+}
+
+func (r *Room) SetRater(rater Rater) {
+	r.rater = rater
+}
+
+func (r *Room) rateGame(points map[string]int) map[string]RatingChange {
+	if r.rater == nil || r.options.Mode == CoopMode || r.options.StreetList == nil {
+		return nil
+	}
+	profiles := make(map[string]string)
+	profilePoints := make(map[string]int)
+	for key, player := range r.players {
+		if player.Profile == "" {
+			continue
+		}
+		profiles[player.Profile] = key
+		profilePoints[player.Profile] = points[key] - r.carriedPoints[key]
+	}
+	if len(profilePoints) < 2 {
+		return nil
+	}
+	changes := r.rater.Rate(r.options.StreetList.FileName, profilePoints)
+	result := make(map[string]RatingChange, len(changes))
+	for profile, change := range changes {
+		result[profiles[profile]] = change
+	}
+	return result
+}
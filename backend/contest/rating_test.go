@@ -0,0 +1,92 @@
+package contest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testRater struct {
+	streetList string
+	points     map[string]int
+}
+
+func (r *testRater) Rate(streetList string, points map[string]int) map[string]RatingChange {
+	r.streetList = streetList
+	r.points = points
+	result := make(map[string]RatingChange, len(points))
+	for profile, value := range points {
+		result[profile] = RatingChange{ProfileId: profile, Delta: value}
+	}
+	return result
+}
+
+func TestRoom_rateGame(t *testing.T) {
+	tests := []struct {
+		name       string
+		mode       GameMode
+		profiles   []string
+		carried    []int
+		wantPoints map[string]int
+	}{
+		{
+			name:       "two profiles",
+			mode:       ClassicMode,
+			profiles:   []string{"alice", "bob"},
+			wantPoints: map[string]int{"alice": 100, "bob": 50},
+		},
+		{
+			name:       "anonymous players are not rated",
+			mode:       ClassicMode,
+			profiles:   []string{"alice", "bob", ""},
+			wantPoints: map[string]int{"alice": 100, "bob": 50},
+		},
+		{
+			name:       "carried points are not rated",
+			mode:       ClassicMode,
+			profiles:   []string{"alice", "bob"},
+			carried:    []int{90, 0},
+			wantPoints: map[string]int{"alice": 10, "bob": 50},
+		},
+		{name: "single profile", mode: ClassicMode, profiles: []string{"alice", ""}},
+		{name: "cooperative game", mode: CoopMode, profiles: []string{"alice", "bob"}},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				room := newTestRoom(
+					func(options *RoomOptions) {
+						options.Mode = tt.mode
+					},
+				)
+				rater := &testRater{}
+				room.SetRater(rater)
+				points := make(map[string]int)
+				room.carriedPoints = make(map[string]int)
+				keys := make(map[string]string)
+				for index, profile := range tt.profiles {
+					player, _ := joinConnected(room, "Player")
+					room.SetProfile(player.Key, profile)
+					points[player.Key] = 100 - 50*index
+					if index < len(tt.carried) {
+						room.carriedPoints[player.Key] = tt.carried[index]
+					}
+					keys[profile] = player.Key
+				}
+				changes := room.rateGame(points)
+				if tt.wantPoints == nil {
+					assert.Nil(t, changes)
+					assert.Nil(t, rater.points)
+					return
+				}
+				assert.Equal(t, "test.json", rater.streetList)
+				assert.Equal(t, tt.wantPoints, rater.points)
+				assert.Len(t, changes, len(tt.wantPoints))
+				for profile, want := range tt.wantPoints {
+					assert.Equal(t, want, changes[keys[profile]].Delta, profile)
+					assert.Equal(t, profile, changes[keys[profile]].ProfileId)
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,75 @@
+package contest
+
+import "time"
+
+const idleTimeout = 30 * time.Minute
+
+const maxGameDuration = 24 * time.Hour
+
+func (r *Room) CanRematch() bool {
+	return r.started && r.finished && len(r.players) > 0
+}
+
+func (r *Room) Rematch(playerKey string, keepScores bool) {
+	r.games = r.games + 1
+	r.carriedPoints = nil
+	if keepScores {
+		r.carriedPoints = r.lastPoints
+	}
+	r.started = false
+	r.finished = false
+	r.currentQuestion = nil
+	r.nextQuestion = 0
+	r.nextStreet = 0
+	for _, player := range r.players {
+		player.firstQuestion = 0
+	}
+	points := r.startingPoints()
+	game := r.games + 1
+	r.notifyPlayers(
+		func(player Player) {
+			player.NotifyRematch(playerKey, game, points)
+		},
+	)
+}
+
+func (r *Room) startingPoints() map[string]int {
+	result := make(map[string]int, len(r.players))
+	for key := range r.players {
+		if points, ok := r.carriedPoints[key]; ok {
+			result[key] = points
+		}
+	}
+	return result
+}
+
+func (r *Room) Idle(now time.Time) bool {
+	if r.occupied() {
+		return false
+	}
+	if len(r.players) == 0 {
+		return true
+	}
+	if r.started && !r.finished {
+		return now.Sub(r.startedAt) > maxGameDuration
+	}
+	lastActivity := r.events.lastActivity()
+	if lastActivity.Before(r.creation) {
+		lastActivity = r.creation
+	}
+	return now.Sub(lastActivity) > idleTimeout
+}
+
+func (r *Room) occupied() bool {
+	for _, player := range r.players {
+		if player.connected {
+			return true
+		}
+	}
+	for _, spectator := range r.spectators {
+		if spectator.connected {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package contest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoom_CanRematch(t *testing.T) {
+	tests := []struct {
+		name     string
+		started  bool
+		finished bool
+		players  int
+		want     bool
+	}{
+		{name: "waiting", players: 1},
+		{name: "running", started: true, players: 1},
+		{name: "finished", started: true, finished: true, players: 1, want: true},
+		{name: "finished without players", started: true, finished: true},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				room := newTestRoom(nil)
+				for index := 0; index < tt.players; index++ {
+					joinConnected(room, "Player")
+				}
+				room.started = tt.started
+				room.finished = tt.finished
+				assert.Equal(t, tt.want, room.CanRematch())
+			},
+		)
+	}
+}
+
+func TestRoom_Rematch(t *testing.T) {
+	tests := []struct {
+		name       string
+		keepScores bool
+		want       map[string]int
+	}{
+		{name: "fresh scores", want: map[string]int{}},
+		{name: "keep scores", keepScores: true, want: map[string]int{"Alice": 40, "Bob": 10}},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				room := newTestRoom(nil)
+				alice, notifier := joinConnected(room, "Alice")
+				bob, _ := joinConnected(room, "Bob")
+				names := map[string]string{alice.Key: "Alice", bob.Key: "Bob"}
+				room.Lock()
+				room.started = true
+				room.finished = true
+				room.lastPoints = map[string]int{alice.Key: 40, bob.Key: 10}
+				room.Rematch(alice.Key, tt.keepScores)
+				assert.False(t, room.Started())
+				assert.False(t, room.Finished())
+				points := room.startingPoints()
+				room.Unlock()
+				byName := make(map[string]int)
+				for key, value := range points {
+					byName[names[key]] = value
+				}
+				assert.Equal(t, tt.want, byName)
+				notified := notifier.await(t, "rematch", 1)[0]
+				assert.Equal(t, points, notified)
+			},
+		)
+	}
+}
+
+func TestRoom_Idle(t *testing.T) {
+	tests := []struct {
+		name       string
+		players    int
+		connected  int
+		spectators int
+		started    bool
+		finished   bool
+		later      time.Duration
+		want       bool
+	}{
+		{name: "empty", want: true},
+		{name: "recently active", players: 1, later: time.Minute},
+		{name: "inactive", players: 1, later: idleTimeout + time.Minute, want: true},
+		{name: "inactive but connected", players: 1, connected: 1, later: idleTimeout + time.Minute},
+		{name: "connected spectator only", spectators: 1, later: idleTimeout + time.Minute},
+		{name: "running game", players: 1, started: true, later: idleTimeout + time.Minute},
+		{name: "running for more than a day", players: 1, started: true, later: maxGameDuration + time.Minute, want: true},
+		{
+			name:    "running for more than a day but connected",
+			players: 1, connected: 1, started: true, later: maxGameDuration + time.Minute,
+		},
+		{name: "finished and inactive", players: 1, started: true, finished: true, later: idleTimeout + time.Minute, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				room := newTestRoom(nil)
+				for index := 0; index < tt.players; index++ {
+					if index < tt.connected {
+						joinConnected(room, "Player")
+					} else {
+						room.Join("Player", "Player")
+					}
+				}
+				for index := 0; index < tt.spectators; index++ {
+					spectator := room.Spectate("Screen", "Screen")
+					room.Connect(spectator.Key, &testNotifier{}, "Screen")
+				}
+				now := time.Now()
+				room.started = tt.started
+				room.finished = tt.finished
+				room.startedAt = now
+				assert.Equal(t, tt.want, room.Idle(now.Add(tt.later)))
+			},
+		)
+	}
+}
@@ -3,6 +3,7 @@ package contest
 import (
 	"fmt"
 	"hash/fnv"
+	"log"
 	"math"
 	"math/rand"
 	"sync"
@@ -18,13 +19,51 @@ type Room struct {
 	key             string
 	creation        time.Time
 	players         map[string]*Player
+	spectators      map[string]*Player
+	displays        map[Display]bool
+	displayToken    string
+	exportToken     string
+	events          *eventLog
+	ranks           map[string]int
+	host            string
+	coHosts         map[string][]Permission
+	kickVotes       map[string]*kickVote
+	bannedProfiles  map[string]bool
+	bannedAddresses map[string]bool
+	passwordSalt    []byte
+	passwordHash    []byte
+	inviteOnly      bool
+	invites         map[string]time.Time
 	points          map[string]int
 	random          *rand.Rand
 	options         RoomOptions
 	currentQuestion *Question
+	blitzQuestions  map[string]*Question
+	blitzStreams    *sync.WaitGroup
+	blitzGameOver   chan bool
+	blitzDeadline   time.Time
+	nextQuestion    int
 	advanceGame     chan bool
+	readyPlayers    map[string]bool
+	solvedQuestions int
+	lastPoints      map[string]int
+	questionRecords []QuestionRecord
+	summaries       []GameSummary
+	archive         Archive
+	rater           Rater
+	challenge       string
+	streets         []geodata.Street
+	nextStreet      int
+	ghosts          []Ghost
+	carriedPoints   map[string]int
+	games           int
 	finished        bool
 	started         bool
+	startedAt       time.Time
+	paused          bool
+	pausedSince     time.Time
+	resume          chan bool
+	blitzPause      chan chan bool
 	quit            chan bool
 }
 
@@ -32,47 +71,100 @@ func (r *Room) Key() string {
 	return r.key
 }
 
+type GameMode string
+
+const (
+	ClassicMode GameMode = "classic"
+	BlitzMode   GameMode = "blitz"
+	CoopMode    GameMode = "coop"
+)
+
 type RoomOptions struct {
+	Name              string
+	Public            bool
 	StreetList        *geodata.StreetList
+	Mode              GameMode
 	NumberOfQuestions int
 	MaxAnswerTime     time.Duration
+	GameDuration      time.Duration
+	CoopTarget        int
+	LockIn            bool
+	AutoAdvance       bool
+	ReviewTime        time.Duration
+	KickQuorum        int
+	GracePeriod       time.Duration
+	MaxPlayers        int
+	MinPlayersToStart int
+	RefuseLateJoin    bool
+	LateJoinScore     LateJoinScore
 }
 
 type Question struct {
 	Street             geodata.Street
 	points             map[string]int
+	answers            map[string]types.Coordinate
+	responseTimes      map[string]time.Duration
+	attempts           map[string]int
+	guesses            map[string]*provisionalGuess
 	allPlayersAnswered chan bool
 	begin              time.Time
 	duration           time.Duration
+	deadline           time.Time
 	number             int
+	player             string
+	solvedBy           string
+	finished           bool
+	paused             time.Duration
+	pause              chan chan bool
 	quit               chan bool
 }
 
-func (q *Question) waitForPlayers(countdown func(int)) {
-	t2 := time.NewTimer(q.duration - (1 * time.Second))
-	t1 := time.NewTimer(q.duration - (2 * time.Second))
-	finish := time.NewTimer(q.duration)
-	finished := false
-	for !finished {
+func (q *Question) finish() {
+	if q.finished {
+		return
+	}
+	q.finished = true
+	q.allPlayersAnswered <- true
+}
+
+func (q *Question) elapsed(answered time.Time) time.Duration {
+	return answered.Sub(q.begin) - q.paused
+}
+
+func (q *Question) score(elapsed time.Duration) int {
+	percent := 1.0 * float64(elapsed.Milliseconds()) / float64(q.duration.Milliseconds())
+	return int(math.Max(10, 100-(100*percent)))
+}
+
+func (q *Question) waitForPlayers(countdown func(int), deadlineChanged func(time.Time)) {
+	deadline := time.Now().Add(q.duration)
+	deadlineChanged(deadline)
+	followUps := 1
+	for {
+		timer := time.NewTimer(time.Until(deadline.Add(-time.Duration(followUps+1) * time.Second)))
 		select {
 		case <-q.allPlayersAnswered:
-			t1.Stop()
-			t2.Stop()
-			finish.Stop()
-			finished = true
-		case <-t1.C:
-			countdown(1)
-		case <-t2.C:
-			countdown(0)
-			t1.Stop()
-		case <-finish.C:
-			t1.Stop()
-			t2.Stop()
-			finished = true
+			timer.Stop()
+			return
+		case <-timer.C:
+			if followUps < 0 {
+				return
+			}
+			countdown(followUps)
+			followUps = followUps - 1
+		case resume := <-q.pause:
+			timer.Stop()
+			pausedAt := time.Now()
+			select {
+			case <-resume:
+			case <-q.quit:
+				return
+			}
+			deadline = deadline.Add(time.Since(pausedAt))
+			deadlineChanged(deadline)
 		case <-q.quit:
-			t1.Stop()
-			t2.Stop()
-			finished = true
+			timer.Stop()
+			return
 		}
 	}
 }
@@ -82,12 +174,63 @@ func (r *RoomOptions) Errors() []string {
 	if r.StreetList == nil {
 		errors = append(errors, "streetListMissing")
 	}
-	if r.NumberOfQuestions < 1 {
+	if len(r.Name) > 50 {
+		errors = append(errors, "nameToLong")
+	}
+	if r.Mode != ClassicMode && r.Mode != BlitzMode && r.Mode != CoopMode {
+		errors = append(errors, "modeUnknown")
+	}
+	if r.Mode != BlitzMode && r.NumberOfQuestions < 1 {
 		errors = append(errors, "numberOfQuestionsToSmall")
 	}
-	if r.NumberOfQuestions > 100 {
+	if r.Mode != BlitzMode && r.NumberOfQuestions > 100 {
 		errors = append(errors, "numberOfQuestionsToBig")
 	}
+	if r.Mode == CoopMode && r.CoopTarget < 1 {
+		errors = append(errors, "coopTargetToSmall")
+	}
+	if r.Mode == CoopMode && r.CoopTarget > r.NumberOfQuestions {
+		errors = append(errors, "coopTargetToBig")
+	}
+	if r.Mode == BlitzMode && r.LockIn {
+		errors = append(errors, "lockInUnsupported")
+	}
+	if r.Mode == BlitzMode && r.GameDuration < time.Minute {
+		errors = append(errors, "gameDurationToSmall")
+	}
+	if r.Mode == BlitzMode && r.GameDuration > 30*time.Minute {
+		errors = append(errors, "gameDurationToBig")
+	}
+	if r.AutoAdvance && r.ReviewTime < 3*time.Second {
+		errors = append(errors, "reviewTimeToSmall")
+	}
+	if r.AutoAdvance && r.ReviewTime > 120*time.Second {
+		errors = append(errors, "reviewTimeToBig")
+	}
+	if r.KickQuorum < 1 || r.KickQuorum > 100 {
+		errors = append(errors, "kickQuorumInvalid")
+	}
+	if r.LateJoinScore != ZeroLateJoinScore && r.LateJoinScore != LowestLateJoinScore && r.LateJoinScore != AverageLateJoinScore {
+		errors = append(errors, "lateJoinScoreUnknown")
+	}
+	if r.MaxPlayers < 1 {
+		errors = append(errors, "maxPlayersToSmall")
+	}
+	if r.MaxPlayers > 100 {
+		errors = append(errors, "maxPlayersToBig")
+	}
+	if r.MinPlayersToStart < 1 {
+		errors = append(errors, "minPlayersToStartToSmall")
+	}
+	if r.MinPlayersToStart > r.MaxPlayers {
+		errors = append(errors, "minPlayersToStartToBig")
+	}
+	if r.GracePeriod < 10*time.Second {
+		errors = append(errors, "gracePeriodToSmall")
+	}
+	if r.GracePeriod > 30*time.Minute {
+		errors = append(errors, "gracePeriodToBig")
+	}
 	if r.MaxAnswerTime < 10*time.Second {
 		errors = append(errors, "maxAnswerTimeToSmall")
 	}
@@ -118,13 +261,32 @@ func NewRoom(seedText string) *Room {
 		seed = int64(hashFunc.Sum32())
 	}
 	return &Room{
-		key:      keygen.RoomKey(),
-		creation: time.Now(),
-		random:   rand.New(rand.NewSource(seed)),
-		players:  make(map[string]*Player),
+		key:             keygen.RoomKey(),
+		creation:        time.Now(),
+		random:          rand.New(rand.NewSource(seed)),
+		players:         make(map[string]*Player),
+		spectators:      make(map[string]*Player),
+		displays:        make(map[Display]bool),
+		displayToken:    keygen.PlayerKey(),
+		exportToken:     keygen.PlayerKey(),
+		events:          &eventLog{},
+		coHosts:         make(map[string][]Permission),
+		kickVotes:       make(map[string]*kickVote),
+		bannedProfiles:  make(map[string]bool),
+		bannedAddresses: make(map[string]bool),
+		invites:         make(map[string]time.Time),
 		options: RoomOptions{
+			Mode:              ClassicMode,
 			MaxAnswerTime:     120 * time.Second,
 			NumberOfQuestions: 10,
+			GameDuration:      5 * time.Minute,
+			CoopTarget:        8,
+			ReviewTime:        15 * time.Second,
+			KickQuorum:        DefaultKickQuorum,
+			GracePeriod:       DefaultGracePeriod,
+			MaxPlayers:        DefaultMaxPlayers,
+			MinPlayersToStart: 1,
+			LateJoinScore:     ZeroLateJoinScore,
 		},
 		quit: make(chan bool),
 	}
@@ -151,14 +313,27 @@ func (r *Room) SetOptions(options RoomOptions, playerKey string) {
 	)
 }
 
-func (r *Room) Join(name string) Player {
-	player := Player{Name: name, Secret: keygen.PlayerKey(), Key: keygen.PlayerKey()}
+func (r *Room) Join(name string, address string) Player {
+	player := Player{
+		Name:    name,
+		Secret:  keygen.PlayerKey(),
+		Key:     keygen.PlayerKey(),
+		joined:  time.Now(),
+		address: address,
+	}
 	r.notifyPlayers(
 		func(p Player) {
 			p.NotifyPlayerJoined(player.Name, player.Key)
 		},
 	)
 	r.players[player.Key] = &player
+	r.awaitPresence(&player)
+	if r.host == "" {
+		r.host = player.Key
+	}
+	if r.started && !r.finished {
+		r.admitLateJoiner(&player)
+	}
 	return player
 }
 
@@ -169,10 +344,7 @@ func (r *Room) Leave(playerKey string) Player {
 			p.NotifyPlayerLeft(player.Name, player.Key)
 		},
 	)
-	delete(r.players, playerKey)
-	if len(r.players) == 0 {
-		r.finished = true
-	}
+	r.removePlayer(playerKey)
 	return *player
 }
 
@@ -183,11 +355,22 @@ func (r *Room) Kick(target string, initiator string) Player {
 			p.NotifyPlayerKicked(kicked.Key, kicked.Name, initiator)
 		},
 	)
-	delete(r.players, target)
+	r.removePlayer(target)
+	return *kicked
+}
+
+func (r *Room) removePlayer(playerKey string) {
+	if r.host == playerKey {
+		r.migrateHost()
+	}
+	delete(r.players, playerKey)
+	delete(r.coHosts, playerKey)
+	r.cancelKickVote(playerKey)
+	r.withdrawKickVotes(playerKey)
 	if len(r.players) == 0 {
 		r.finished = true
 	}
-	return *kicked
+	r.finishIfEveryoneAnswered()
 }
 
 func (r *Room) FindPlayer(key string) (*Player, bool) {
@@ -195,12 +378,57 @@ func (r *Room) FindPlayer(key string) (*Player, bool) {
 	return result, ok
 }
 
+func (r *Room) Connect(playerKey string, notifier Notifier, address string) {
+	player, ok := r.players[playerKey]
+	if !ok {
+		player = r.spectators[playerKey]
+	}
+	player.Notifier = notifier
+	player.address = address
+	if _, ok := r.players[playerKey]; ok {
+		r.setPresence(player, true)
+	} else {
+		player.connected = true
+	}
+}
+
+func (r *Room) Disconnect(playerKey string, notifier Notifier) {
+	if spectator, ok := r.spectators[playerKey]; ok && spectator.Notifier == notifier {
+		spectator.Notifier = nil
+		r.awaitSpectator(spectator)
+		return
+	}
+	player, ok := r.players[playerKey]
+	if !ok || player.Notifier != notifier {
+		return
+	}
+	player.Notifier = nil
+	r.setPresence(player, false)
+}
+
 func (r *Room) notifyPlayers(consumer func(Player)) {
+	r.broadcast(r.events.append("", consumer))
+}
+
+func (r *Room) notifyPlayersUnrecorded(consumer func(Player)) {
+	r.broadcast(event{consumer: consumer})
+}
+
+func (r *Room) broadcast(recorded event) {
 	for _, player := range r.players {
 		if player.Notifier == nil {
 			continue
 		}
-		go consumer(*player)
+		go recorded.deliver(player.Notifier)
+	}
+	for _, spectator := range r.spectators {
+		if spectator.Notifier == nil {
+			continue
+		}
+		go recorded.deliver(spectator.Notifier)
+	}
+	for display := range r.displays {
+		go recorded.play(display)
 	}
 }
 
@@ -218,94 +446,188 @@ func (r *Room) Play(playerKey string) {
 		},
 	)
 	numberOfQuestions := r.options.NumberOfQuestions
-	r.points = make(map[string]int)
-	go func() {
+	r.points = r.startingPoints()
+	r.ranks = make(map[string]int)
+	r.questionRecords = nil
+	r.solvedQuestions = 0
+	r.nextQuestion = 0
+	r.startedAt = time.Now()
+	if r.options.Mode == BlitzMode {
 		r.started = true
-	GameLoop:
+		go r.playBlitz()
+		return
+	}
+	r.started = true
+	go func() {
 		for round := 0; round < numberOfQuestions; round++ {
 			err := r.playQuestion(round)
 			if err != nil {
 				break
 			}
-			r.advanceGame = make(chan bool)
-			select {
-			case <-r.advanceGame:
+			if r.coopDecided(round) {
+				break
+			}
+			if !r.waitForAdvance() {
 				break
-			case <-r.quit:
-				break GameLoop
 			}
-			r.advanceGame = nil
 		}
+		r.Lock()
+		defer r.Unlock()
+		reason := r.gameEndReason()
+		points := r.points
+		summary := r.summarize(points)
+		summary.RatingChanges = r.rateGame(points)
+		r.summaries = append(r.summaries, summary)
+		r.archiveGame(summary)
 		r.notifyPlayers(
 			func(player Player) {
-				player.NotifyGameEnded("finished", r.points)
+				player.NotifyGameEnded(reason, points, summary)
 			},
 		)
+		r.lastPoints = points
 		r.points = nil
 		r.finished = true
 	}()
 }
 
+func (r *Room) coopDecided(round int) bool {
+	if r.options.Mode != CoopMode {
+		return false
+	}
+	remaining := r.options.NumberOfQuestions - round - 1
+	return r.solvedQuestions >= r.options.CoopTarget || r.solvedQuestions+remaining < r.options.CoopTarget
+}
+
+func (r *Room) gameEndReason() string {
+	if r.options.Mode != CoopMode {
+		return "finished"
+	}
+	if r.solvedQuestions >= r.options.CoopTarget {
+		return "coopSucceeded"
+	}
+	return "coopFailed"
+}
+
 func (r *Room) Close() error {
 	close(r.quit)
 	return nil
 }
 
-func (r *Room) playQuestion(round int) error {
+func (r *Room) randomStreet() (geodata.Street, error) {
+	r.Lock()
+	if r.streets != nil {
+		defer r.Unlock()
+		if r.nextStreet >= len(r.streets) {
+			return geodata.Street{}, fmt.Errorf("no recorded street left for question %d", r.nextStreet+1)
+		}
+		street := r.streets[r.nextStreet]
+		r.nextStreet = r.nextStreet + 1
+		return street, nil
+	}
+	streetList := r.options.StreetList
+	r.Unlock()
 	tries := 0
-	randomStreet, err := r.options.StreetList.GetRandomStreet(r.random)
+	randomStreet, err := streetList.GetRandomStreet(r.random)
 	for tries < 10 && err != nil {
-		randomStreet, err = r.options.StreetList.GetRandomStreet(r.random)
+		randomStreet, err = streetList.GetRandomStreet(r.random)
 		tries = tries + 1
 	}
 	if randomStreet.Coordinate == nil {
+		return randomStreet, fmt.Errorf("repeatedly failed to get random street")
+	}
+	return randomStreet, nil
+}
+
+func (r *Room) playQuestion(round int) error {
+	randomStreet, err := r.randomStreet()
+	if err != nil {
+		r.Lock()
+		points := r.copyPoints()
+		r.Unlock()
 		r.notifyPlayers(
 			func(player Player) {
-				player.NotifyGameEnded("repeatedly failed to get random street", r.points)
+				player.NotifyGameEnded(err.Error(), points, GameSummary{HardestQuestion: -1})
 			},
 		)
-		return fmt.Errorf("repeatedly failed to get random street")
+		return err
 	}
 	r.Lock()
 	r.currentQuestion = &Question{
 		Street:             randomStreet,
 		points:             make(map[string]int),
-		allPlayersAnswered: make(chan bool),
+		answers:            make(map[string]types.Coordinate),
+		responseTimes:      make(map[string]time.Duration),
+		attempts:           make(map[string]int),
+		guesses:            make(map[string]*provisionalGuess),
+		allPlayersAnswered: make(chan bool, 1),
 		begin:              time.Now(),
 		duration:           r.options.MaxAnswerTime,
 		number:             round,
+		pause:              make(chan chan bool, 1),
 		quit:               r.quit,
 	}
+	r.nextQuestion = round + 1
+	r.pauseIfNeeded(r.currentQuestion)
 	r.Unlock()
 	r.sendCountdowns(
 		3, func(player Player) func(int, int) {
 			return player.NotifyQuestionCountdown
 		}, round,
 	)
+	r.Lock()
 	r.notifyPlayers(
 		func(player Player) {
 			player.NotifyQuestion(randomStreet.Name, round)
 		},
 	)
+	r.replayGhosts(r.currentQuestion)
+	r.Unlock()
 	r.currentQuestion.waitForPlayers(
 		func(followUps int) {
+			r.Lock()
+			defer r.Unlock()
 			r.notifyPlayers(
 				func(player Player) {
 					player.NotifyAnswerTimeCountdown(followUps)
 				},
 			)
 		},
+		func(deadline time.Time) {
+			r.Lock()
+			defer r.Unlock()
+			r.currentQuestion.deadline = deadline
+			r.notifyDisplays(
+				func(display Display) {
+					display.NotifyQuestionDeadline(round, deadline)
+				},
+			)
+		},
 	)
-	for key, value := range r.currentQuestion.points {
+	r.Lock()
+	r.currentQuestion.finished = true
+	r.Unlock()
+	if r.options.LockIn {
+		r.evaluateGuesses(r.currentQuestion)
+	}
+	pointDelta := r.currentQuestion.points
+	if r.options.Mode == CoopMode {
+		pointDelta = r.coopPointDelta(r.currentQuestion)
+	}
+	r.Lock()
+	for key, value := range pointDelta {
 		r.points[key] = r.points[key] + value
 	}
+	r.recordQuestion(r.currentQuestion, pointDelta)
 	result := QuestionResult{
-		Question:       randomStreet.Name,
-		Solution:       *randomStreet.Coordinate,
-		PointDelta:     r.currentQuestion.points,
-		Points:         r.points,
-		QuestionNumber: round,
+		Question:        randomStreet.Name,
+		Solution:        *randomStreet.Coordinate,
+		PointDelta:      pointDelta,
+		Points:          r.copyPoints(),
+		QuestionNumber:  round,
+		SolvedQuestions: r.solvedQuestions,
+		Guesses:         r.currentQuestion.answers,
 	}
+	r.Unlock()
 	r.notifyPlayers(
 		func(player Player) {
 			player.NotifyQuestionResults(result)
@@ -313,66 +635,349 @@ func (r *Room) playQuestion(round int) error {
 	)
 	r.Lock()
 	r.currentQuestion = nil
+	r.notifyScoreboard()
 	r.Unlock()
 	return nil
 }
 
+func (r *Room) playBlitz() {
+	gameOver := make(chan bool)
+	deadline := time.Now().Add(r.options.GameDuration)
+	streams := &sync.WaitGroup{}
+	r.Lock()
+	r.blitzQuestions = make(map[string]*Question)
+	r.blitzPause = make(chan chan bool, 1)
+	r.blitzStreams = streams
+	r.blitzGameOver = gameOver
+	r.blitzDeadline = deadline
+	duration := r.options.GameDuration
+	r.notifyPlayers(
+		func(player Player) {
+			player.NotifyGameDeadline(duration)
+		},
+	)
+	for key := range r.players {
+		r.startBlitzStream(key)
+	}
+	r.Unlock()
+	for running := true; running; {
+		timer := time.NewTimer(time.Until(deadline))
+		select {
+		case <-timer.C:
+			running = false
+		case resume := <-r.blitzPause:
+			timer.Stop()
+			pausedAt := time.Now()
+			select {
+			case <-resume:
+			case <-r.quit:
+				running = false
+			}
+			deadline = deadline.Add(time.Since(pausedAt))
+			r.Lock()
+			r.blitzDeadline = deadline
+			r.Unlock()
+		case <-r.quit:
+			timer.Stop()
+			running = false
+		}
+	}
+	r.Lock()
+	close(gameOver)
+	r.blitzGameOver = nil
+	r.Unlock()
+	streams.Wait()
+	r.Lock()
+	r.blitzQuestions = nil
+	r.blitzPause = nil
+	r.blitzStreams = nil
+	points := r.points
+	summary := r.summarize(points)
+	summary.RatingChanges = r.rateGame(points)
+	r.summaries = append(r.summaries, summary)
+	r.archiveGame(summary)
+	r.notifyPlayers(
+		func(player Player) {
+			player.NotifyGameEnded("finished", points, summary)
+		},
+	)
+	r.lastPoints = points
+	r.points = nil
+	r.finished = true
+	r.Unlock()
+}
+
+func (r *Room) startBlitzStream(playerKey string) {
+	streams := r.blitzStreams
+	gameOver := r.blitzGameOver
+	streams.Add(1)
+	go func() {
+		defer streams.Done()
+		r.playBlitzStream(playerKey, gameOver)
+	}()
+}
+
+func (r *Room) playBlitzStream(playerKey string, gameOver chan bool) {
+	for round := 0; ; round++ {
+		select {
+		case <-gameOver:
+			return
+		default:
+		}
+		randomStreet, err := r.randomStreet()
+		if err != nil {
+			log.Printf("blitz stream of player \"%s\" in room \"%s\" ended: %v", playerKey, r.key, err)
+			return
+		}
+		question := &Question{
+			Street:             randomStreet,
+			points:             make(map[string]int),
+			answers:            make(map[string]types.Coordinate),
+			responseTimes:      make(map[string]time.Duration),
+			attempts:           make(map[string]int),
+			allPlayersAnswered: make(chan bool, 1),
+			begin:              time.Now(),
+			duration:           r.options.MaxAnswerTime,
+			number:             round,
+			player:             playerKey,
+			pause:              make(chan chan bool, 1),
+			quit:               gameOver,
+		}
+		r.Lock()
+		if _, ok := r.players[playerKey]; !ok {
+			r.Unlock()
+			return
+		}
+		r.blitzQuestions[playerKey] = question
+		r.pauseIfNeeded(question)
+		r.notifyPlayer(
+			playerKey, func(player Player) {
+				player.NotifyQuestion(randomStreet.Name, round)
+			},
+		)
+		r.Unlock()
+		question.waitForPlayers(
+			func(followUps int) {
+				r.Lock()
+				defer r.Unlock()
+				r.notifyPlayer(
+					playerKey, func(player Player) {
+						player.NotifyAnswerTimeCountdown(followUps)
+					},
+				)
+			},
+			func(time.Time) {},
+		)
+		r.Lock()
+		delete(r.blitzQuestions, playerKey)
+		r.points[playerKey] = r.points[playerKey] + question.points[playerKey]
+		r.recordQuestion(question, question.points)
+		select {
+		case <-gameOver:
+			r.Unlock()
+			return
+		default:
+		}
+		result := QuestionResult{
+			Question:       randomStreet.Name,
+			Solution:       *randomStreet.Coordinate,
+			PointDelta:     map[string]int{playerKey: question.points[playerKey]},
+			Points:         r.copyPoints(),
+			QuestionNumber: round,
+			Guesses:        question.answers,
+		}
+		r.notifyPlayer(
+			playerKey, func(player Player) {
+				player.NotifyQuestionResults(result)
+			},
+		)
+		r.notifyScoreboard()
+		r.Unlock()
+	}
+}
+
+func (r *Room) notifyPlayer(playerKey string, consumer func(Player)) {
+	recorded := r.events.append(playerKey, consumer)
+	player, ok := r.players[playerKey]
+	if !ok || player.Notifier == nil {
+		return
+	}
+	go recorded.deliver(player.Notifier)
+}
+
+func (r *Room) copyPoints() map[string]int {
+	result := make(map[string]int, len(r.points))
+	for key, value := range r.points {
+		result[key] = value
+	}
+	return result
+}
+
+func (r *Room) questionFor(playerKey string) *Question {
+	if question, ok := r.blitzQuestions[playerKey]; ok {
+		return question
+	}
+	return r.currentQuestion
+}
+
+func (r *Room) coopPointDelta(question *Question) map[string]int {
+	r.Lock()
+	defer r.Unlock()
+	value := 0
+	if question.solvedBy != "" {
+		value = question.points[question.solvedBy]
+		r.solvedQuestions = r.solvedQuestions + 1
+	}
+	result := make(map[string]int, len(r.players))
+	for key := range r.players {
+		if r.participates(key, question) {
+			result[key] = value
+		}
+	}
+	return result
+}
+
 func (r *Room) AnswerQuestion(playerKey string, guess types.Coordinate) (int, error) {
 	_, ok := r.players[playerKey]
 	if !ok {
 		panic(fmt.Sprintf("player with key \"%s\" not found in this room", playerKey))
 	}
-	question := r.currentQuestion
+	question := r.questionFor(playerKey)
+	question.answers[playerKey] = guess
+	question.responseTimes[playerKey] = question.elapsed(time.Now())
+	question.attempts[playerKey] = question.attempts[playerKey] + 1
 	result, err := geodata.VerifyAnswer(guess, question.Street.Name)
 	if result {
-		difference := time.Now().Sub(question.begin)
-		percent := 1.0 * float64(difference.Milliseconds()) / float64(question.duration.Milliseconds())
-		question.points[playerKey] = int(math.Max(10, 100-(100*percent)))
+		question.points[playerKey] = question.score(question.elapsed(time.Now()))
 	} else {
 		question.points[playerKey] = 0
 	}
+	points := question.points[playerKey]
 	r.notifyPlayers(
 		func(player Player) {
-			player.NotifyPlayerAnswered(playerKey, question.points[playerKey])
+			player.NotifyPlayerAnswered(playerKey, points)
 		},
 	)
-	if len(question.points) == len(r.players) {
-		question.allPlayersAnswered <- true
+	r.notifyAnswerCount(len(question.points))
+	if r.options.Mode == CoopMode && result && question.solvedBy == "" {
+		question.solvedBy = playerKey
+		question.finish()
+	}
+	if question.player != "" || r.everyoneAnswered(question) {
+		question.finish()
 	}
 	return question.points[playerKey], err
 }
 
 func (r *Room) HasActiveQuestion(playerKey string) bool {
-	if r.currentQuestion == nil {
+	question := r.questionFor(playerKey)
+	if question == nil || question.finished || !r.participates(playerKey, question) {
 		return false
 	}
-	_, ok := r.currentQuestion.points[playerKey]
+	if guess, ok := question.guesses[playerKey]; ok {
+		return !guess.locked
+	}
+	_, ok := question.points[playerKey]
 	return !ok
 }
 
-func (r *Room) Question() (string, int) {
-	return r.currentQuestion.Street.Name, r.currentQuestion.number
+func (r *Room) Question(playerKey string) (string, int) {
+	question := r.questionFor(playerKey)
+	return question.Street.Name, question.number
+}
+
+func (r *Room) waitForAdvance() bool {
+	advance := make(chan bool, 1)
+	r.Lock()
+	r.advanceGame = advance
+	r.readyPlayers = make(map[string]bool)
+	r.Unlock()
+	defer func() {
+		r.Lock()
+		r.advanceGame = nil
+		r.readyPlayers = nil
+		r.Unlock()
+	}()
+	if !r.options.AutoAdvance {
+		select {
+		case <-advance:
+			return true
+		case <-r.quit:
+			return false
+		}
+	}
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	remaining := r.options.ReviewTime
+	for remaining > 0 {
+		nextQuestionIn := remaining
+		r.Lock()
+		if !r.paused {
+			r.notifyPlayersUnrecorded(
+				func(player Player) {
+					player.NotifyNextQuestionIn(nextQuestionIn)
+				},
+			)
+		}
+		r.Unlock()
+		select {
+		case <-advance:
+			return true
+		case <-r.quit:
+			return false
+		case <-ticker.C:
+			r.Lock()
+			if !r.paused {
+				remaining = remaining - time.Second
+			}
+			r.Unlock()
+		}
+	}
+	return true
 }
 
 func (r *Room) CanBeAdvanced() bool {
-	return r.advanceGame != nil
+	return r.advanceGame != nil && !r.paused
 }
 
 func (r *Room) AdvanceToNextQuestion() {
 	r.advanceGame <- true
+	r.advanceGame = nil
+}
+
+func (r *Room) VoteReady(playerKey string) {
+	r.readyPlayers[playerKey] = true
+	r.notifyPlayers(
+		func(player Player) {
+			player.NotifyPlayerReady(playerKey)
+		},
+	)
+	for key, player := range r.players {
+		if player.connected && !r.readyPlayers[key] {
+			return
+		}
+	}
+	r.AdvanceToNextQuestion()
 }
 
 func (r *Room) sendCountdowns(
 	amount int, consumer func(Player) func(int, int), numberOfQuestion int,
 ) {
 	for i := 0; i < amount; i++ {
+		if !r.awaitResume() {
+			return
+		}
+		followUps := amount - i - 1
+		r.Lock()
 		r.notifyPlayers(
 			func(player Player) {
-				consumer(player)(amount-i-1, numberOfQuestion)
+				consumer(player)(followUps, numberOfQuestion)
 			},
 		)
+		r.Unlock()
 		time.Sleep(time.Second)
 	}
+	r.awaitResume()
 }
 
 func (r *Room) Started() bool {
@@ -392,15 +997,23 @@ type Player struct {
 	Secret        string
 	Key           string
 	Name          string
+	Profile       string
 	receivedKicks int
+	joined        time.Time
+	address       string
+	firstQuestion int
+	connected     bool
+	disconnected  time.Time
 }
 
 type QuestionResult struct {
-	Question       string           `json:"question"`
-	Solution       types.Coordinate `json:"solution"`
-	PointDelta     map[string]int   `json:"pointDelta"`
-	Points         map[string]int   `json:"points"`
-	QuestionNumber int              `json:"questionNumber"`
+	Question        string                      `json:"question"`
+	Solution        types.Coordinate            `json:"solution"`
+	PointDelta      map[string]int              `json:"pointDelta"`
+	Points          map[string]int              `json:"points"`
+	QuestionNumber  int                         `json:"questionNumber"`
+	SolvedQuestions int                         `json:"solvedQuestions"`
+	Guesses         map[string]types.Coordinate `json:"guesses"`
 }
 
 type Notifier interface {
@@ -408,11 +1021,26 @@ type Notifier interface {
 	NotifyPlayerLeft(string, string)
 	NotifyRoomUpdated(RoomOptions, string)
 	NotifyGameStarted(playerKey string)
+	NotifyGameDeadline(remaining time.Duration)
+	NotifyGamePaused(playerKey string)
+	NotifyGameResumed(playerKey string)
 	NotifyPlayerAnswered(string, int)
+	NotifyPlayerGuessed(playerKey string, locked bool)
 	NotifyQuestionCountdown(int, int)
 	NotifyQuestion(string, int)
 	NotifyAnswerTimeCountdown(int)
 	NotifyQuestionResults(result QuestionResult)
-	NotifyGameEnded(reason string, result map[string]int)
+	NotifyNextQuestionIn(remaining time.Duration)
+	NotifyPlayerReady(playerKey string)
+	NotifyGameEnded(reason string, result map[string]int, summary GameSummary)
 	NotifyPlayerKicked(string, string, string)
+	NotifyKickVoteStarted(vote KickVote)
+	NotifyKickVoteUpdated(vote KickVote)
+	NotifyHostChanged(playerKey string, name string)
+	NotifyPlayerPresenceChanged(playerKey string, connected bool)
+	NotifyRematch(playerKey string, game int, points map[string]int)
+	NotifyChallengeBeaten(result ChallengeResult)
+	NotifyDailyStanding(standing DailyStanding)
+	NotifyTournamentStage(stage TournamentStage)
+	NotifyTournamentStandings(standings []TournamentStanding)
 }
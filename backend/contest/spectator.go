@@ -0,0 +1,94 @@
+package contest
+
+import (
+	"time"
+
+	"github.com/fafeitsch/city-knowledge-contest/backend/keygen"
+)
+
+func (r *Room) Spectate(name string, address string) Player {
+	spectator := Player{
+		Name:    name,
+		Secret:  keygen.PlayerKey(),
+		Key:     keygen.PlayerKey(),
+		joined:  time.Now(),
+		address: address,
+	}
+	r.spectators[spectator.Key] = &spectator
+	r.awaitSpectator(&spectator)
+	return spectator
+}
+
+func (r *Room) awaitSpectator(spectator *Player) {
+	spectator.connected = false
+	spectator.disconnected = time.Now()
+	disconnected := spectator.disconnected
+	time.AfterFunc(
+		r.options.GracePeriod, func() {
+			r.Lock()
+			defer r.Unlock()
+			r.removeAbsentSpectator(spectator.Key, disconnected)
+		},
+	)
+}
+
+func (r *Room) removeAbsentSpectator(spectatorKey string, disconnected time.Time) {
+	spectator, ok := r.spectators[spectatorKey]
+	if !ok || spectator.connected || !spectator.disconnected.Equal(disconnected) {
+		return
+	}
+	delete(r.spectators, spectatorKey)
+}
+
+func (r *Room) FindSpectator(key string) (*Player, bool) {
+	result, ok := r.spectators[key]
+	return result, ok
+}
+
+func (r *Room) Spectators() []Player {
+	result := make([]Player, 0, len(r.spectators))
+	for key := range r.spectators {
+		result = append(result, *r.spectators[key])
+	}
+	return result
+}
+
+func (r *Room) CanConvertSpectators() bool {
+	return !r.started || r.finished
+}
+
+func (r *Room) ConvertToPlayer(spectatorKey string) Player {
+	spectator := r.spectators[spectatorKey]
+	delete(r.spectators, spectatorKey)
+	r.notifyPlayers(
+		func(p Player) {
+			p.NotifyPlayerJoined(spectator.Name, spectator.Key)
+		},
+	)
+	r.players[spectator.Key] = spectator
+	spectator.connected = false
+	if spectator.Notifier != nil {
+		r.setPresence(spectator, true)
+	} else {
+		r.awaitPresence(spectator)
+	}
+	if r.host == "" {
+		r.host = spectator.Key
+	}
+	return *spectator
+}
+
+func (r *Room) ConvertToSpectator(playerKey string) Player {
+	player := r.players[playerKey]
+	r.notifyPlayers(
+		func(p Player) {
+			p.NotifyPlayerLeft(player.Name, player.Key)
+		},
+	)
+	r.removePlayer(playerKey)
+	r.spectators[player.Key] = player
+	if player.Notifier == nil {
+		r.awaitSpectator(player)
+	}
+	return *player
+}
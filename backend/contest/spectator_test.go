@@ -0,0 +1,99 @@
+package contest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoom_CanConvertSpectators(t *testing.T) {
+	tests := []struct {
+		name     string
+		started  bool
+		finished bool
+		want     bool
+	}{
+		{name: "waiting", want: true},
+		{name: "running", started: true},
+		{name: "finished", started: true, finished: true, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				room := newTestRoom(nil)
+				room.started = tt.started
+				room.finished = tt.finished
+				assert.Equal(t, tt.want, room.CanConvertSpectators())
+			},
+		)
+	}
+}
+
+func TestRoom_Spectate(t *testing.T) {
+	t.Parallel()
+	room := newTestRoom(nil)
+	alice, _ := joinConnected(room, "Alice")
+	spectatorNotifier := &testNotifier{}
+	room.Lock()
+	spectator := room.Spectate("Watcher", "Watcher")
+	room.Connect(spectator.Key, spectatorNotifier, "Watcher")
+	assert.Len(t, room.Players(), 1)
+	assert.Len(t, room.Spectators(), 1)
+	room.Unlock()
+	startGame(t, room, alice.Key)
+	answer(t, room, alice.Key, rightGuess)
+	spectatorNotifier.await(t, "questionResults", 1)
+	awaitAdvance(t, room)
+	ended := spectatorNotifier.awaitEnd(t)
+	assert.NotContains(t, ended.points, spectator.Key)
+	assert.Len(t, ended.summary.Players, 1)
+
+	room.Lock()
+	defer room.Unlock()
+	require.True(t, room.CanConvertSpectators())
+	converted := room.ConvertToPlayer(spectator.Key)
+	assert.Equal(t, spectator.Key, converted.Key)
+	player, ok := room.FindPlayer(spectator.Key)
+	require.True(t, ok)
+	assert.True(t, player.Connected())
+	assert.Empty(t, room.Spectators())
+	room.ConvertToSpectator(spectator.Key)
+	_, ok = room.FindPlayer(spectator.Key)
+	assert.False(t, ok)
+	assert.Len(t, room.Spectators(), 1)
+}
+
+func TestRoom_DisconnectSpectator(t *testing.T) {
+	tests := []struct {
+		name        string
+		reconnect   bool
+		wantRemoved bool
+	}{
+		{name: "spectator stays away", wantRemoved: true},
+		{name: "spectator comes back", reconnect: true},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				room := newTestRoom(nil)
+				room.Lock()
+				defer room.Unlock()
+				spectator := room.Spectate("Watcher", "Watcher")
+				notifier := &testNotifier{}
+				room.Connect(spectator.Key, notifier, "Watcher")
+				room.Disconnect(spectator.Key, notifier)
+				watcher, ok := room.FindSpectator(spectator.Key)
+				require.True(t, ok)
+				assert.False(t, watcher.Connected())
+				disconnected := watcher.disconnected
+				if tt.reconnect {
+					room.Connect(spectator.Key, &testNotifier{}, "Watcher")
+				}
+				room.removeAbsentSpectator(spectator.Key, disconnected)
+				_, ok = room.FindSpectator(spectator.Key)
+				assert.Equal(t, tt.wantRemoved, !ok)
+			},
+		)
+	}
+}
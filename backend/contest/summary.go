@@ -0,0 +1,180 @@
+package contest
+
+import (
+	"math"
+	"sort"
+
+	"github.com/fafeitsch/city-knowledge-contest/backend/types"
+)
+
+type AnswerRecord struct {
+	PlayerKey      string            `json:"playerKey"`
+	Answered       bool              `json:"answered"`
+	Guess          *types.Coordinate `json:"guess,omitempty"`
+	Correct        bool              `json:"correct"`
+	ResponseTimeMs int64             `json:"responseTimeMs"`
+	Points         int               `json:"points"`
+	Attempts       int               `json:"attempts"`
+	DistanceMeters float64           `json:"distanceMeters"`
+}
+
+type QuestionRecord struct {
+	Number   int              `json:"number"`
+	Street   string           `json:"street"`
+	Solution types.Coordinate `json:"solution"`
+	Player   string           `json:"player,omitempty"`
+	Answers  []AnswerRecord   `json:"answers"`
+}
+
+type PlayerSummary struct {
+	PlayerKey     string  `json:"playerKey"`
+	Name          string  `json:"name"`
+	ProfileId     string  `json:"profileId,omitempty"`
+	Points        int     `json:"points"`
+	CarriedPoints int     `json:"carriedPoints,omitempty"`
+	Questions     int     `json:"questions"`
+	Correct       int     `json:"correct"`
+	Accuracy      float64 `json:"accuracy"`
+	AverageTimeMs int64   `json:"averageTimeMs"`
+	FastestTimeMs int64   `json:"fastestTimeMs"`
+	BestQuestion  int     `json:"bestQuestion"`
+}
+
+type GameSummary struct {
+	Game            int                     `json:"game"`
+	Players         []PlayerSummary         `json:"players"`
+	Questions       []QuestionRecord        `json:"questions"`
+	HardestQuestion int                     `json:"hardestQuestion"`
+	RatingChanges   map[string]RatingChange `json:"ratingChanges,omitempty"`
+	Options         RoomOptions             `json:"-"`
+}
+
+func (r *Room) Summary() (GameSummary, bool) {
+	if len(r.summaries) == 0 {
+		return GameSummary{}, false
+	}
+	return r.summaries[len(r.summaries)-1], true
+}
+
+func (r *Room) GameSummary(game int) (GameSummary, bool) {
+	for _, summary := range r.summaries {
+		if summary.Game == game {
+			return summary, true
+		}
+	}
+	return GameSummary{}, false
+}
+
+func (r *Room) ExportToken() string {
+	return r.exportToken
+}
+
+func (r *Room) recordQuestion(question *Question, pointDelta map[string]int) {
+	record := QuestionRecord{
+		Number:   question.number,
+		Street:   question.Street.Name,
+		Solution: *question.Street.Coordinate,
+		Player:   question.player,
+		Answers:  make([]AnswerRecord, 0, len(r.players)),
+	}
+	for key := range r.players {
+		if !r.participates(key, question) {
+			continue
+		}
+		answer := AnswerRecord{PlayerKey: key, Points: pointDelta[key], Attempts: question.attempts[key]}
+		if guess, ok := question.answers[key]; ok {
+			answer.Answered = true
+			answer.Guess = &guess
+			answer.Correct = question.points[key] > 0
+			answer.ResponseTimeMs = question.responseTimes[key].Milliseconds()
+			answer.DistanceMeters = distance(guess, *question.Street.Coordinate)
+		}
+		record.Answers = append(record.Answers, answer)
+	}
+	r.questionRecords = append(r.questionRecords, record)
+}
+
+func (r *Room) summarize(points map[string]int) GameSummary {
+	players := make(map[string]*PlayerSummary)
+	bestPoints := make(map[string]int)
+	totalTimes := make(map[string]int64)
+	hardest := -1
+	hardestRatio := 2.0
+	hardestPoints := 0
+	for index, question := range r.questionRecords {
+		correct := 0
+		questionPoints := 0
+		for _, answer := range question.Answers {
+			summary, ok := players[answer.PlayerKey]
+			if !ok {
+				summary = &PlayerSummary{PlayerKey: answer.PlayerKey, BestQuestion: -1}
+				players[answer.PlayerKey] = summary
+			}
+			summary.Questions = summary.Questions + 1
+			questionPoints = questionPoints + answer.Points
+			if !answer.Correct {
+				continue
+			}
+			correct = correct + 1
+			summary.Correct = summary.Correct + 1
+			totalTimes[answer.PlayerKey] = totalTimes[answer.PlayerKey] + answer.ResponseTimeMs
+			if summary.FastestTimeMs == 0 || answer.ResponseTimeMs < summary.FastestTimeMs {
+				summary.FastestTimeMs = answer.ResponseTimeMs
+			}
+			if summary.BestQuestion < 0 || answer.Points > bestPoints[answer.PlayerKey] {
+				summary.BestQuestion = index
+				bestPoints[answer.PlayerKey] = answer.Points
+			}
+		}
+		if len(question.Answers) == 0 {
+			continue
+		}
+		ratio := float64(correct) / float64(len(question.Answers))
+		if ratio < hardestRatio || (ratio == hardestRatio && questionPoints < hardestPoints) {
+			hardest = index
+			hardestRatio = ratio
+			hardestPoints = questionPoints
+		}
+	}
+	result := GameSummary{
+		Game:            r.games + 1,
+		Players:         make([]PlayerSummary, 0, len(players)),
+		Questions:       r.questionRecords,
+		HardestQuestion: hardest,
+		Options:         r.options,
+	}
+	for key, summary := range players {
+		if player, ok := r.players[key]; ok {
+			summary.Name = player.Name
+			summary.ProfileId = player.Profile
+		}
+		summary.CarriedPoints = r.carriedPoints[key]
+		summary.Points = points[key] - summary.CarriedPoints
+		if summary.Questions > 0 {
+			summary.Accuracy = float64(summary.Correct) / float64(summary.Questions)
+		}
+		if summary.Correct > 0 {
+			summary.AverageTimeMs = totalTimes[key] / int64(summary.Correct)
+		}
+		result.Players = append(result.Players, *summary)
+	}
+	sort.Slice(
+		result.Players, func(i, j int) bool {
+			if result.Players[i].Points != result.Players[j].Points {
+				return result.Players[i].Points > result.Players[j].Points
+			}
+			return result.Players[i].Name < result.Players[j].Name
+		},
+	)
+	return result
+}
+
+func distance(from types.Coordinate, to types.Coordinate) float64 {
+	const earthRadius = 6371000.0
+	lat1 := from.Lat * math.Pi / 180
+	lat2 := to.Lat * math.Pi / 180
+	deltaLat := lat2 - lat1
+	deltaLng := (to.Lng - from.Lng) * math.Pi / 180
+	a := math.Sin(deltaLat/2)*math.Sin(deltaLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(deltaLng/2)*math.Sin(deltaLng/2)
+	return earthRadius * 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
@@ -0,0 +1,183 @@
+package contest
+
+import (
+	"testing"
+
+	"github.com/fafeitsch/city-knowledge-contest/backend/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoom_summarize(t *testing.T) {
+	correct := func(player string, points int, timeMs int64) AnswerRecord {
+		return AnswerRecord{PlayerKey: player, Answered: true, Correct: true, Points: points, ResponseTimeMs: timeMs}
+	}
+	wrong := func(player string) AnswerRecord {
+		return AnswerRecord{PlayerKey: player, Answered: true}
+	}
+	tests := []struct {
+		name        string
+		questions   [][]AnswerRecord
+		points      map[string]int
+		wantHardest int
+		want        map[string]PlayerSummary
+	}{
+		{
+			name:        "no questions",
+			wantHardest: -1,
+			want:        map[string]PlayerSummary{},
+		},
+		{
+			name: "accuracy and response times",
+			questions: [][]AnswerRecord{
+				{correct("alice", 50, 2000), wrong("bob")},
+				{correct("alice", 80, 1000), correct("bob", 40, 3000)},
+			},
+			points:      map[string]int{"alice": 130, "bob": 40},
+			wantHardest: 0,
+			want: map[string]PlayerSummary{
+				"alice": {
+					Points: 130, Questions: 2, Correct: 2, Accuracy: 1, AverageTimeMs: 1500, FastestTimeMs: 1000,
+					BestQuestion: 1,
+				},
+				"bob": {
+					Points: 40, Questions: 2, Correct: 1, Accuracy: 0.5, AverageTimeMs: 3000, FastestTimeMs: 3000,
+					BestQuestion: 1,
+				},
+			},
+		},
+		{
+			name: "hardest question breaks ties by points",
+			questions: [][]AnswerRecord{
+				{correct("alice", 90, 1000), wrong("bob")},
+				{correct("alice", 20, 1000), wrong("bob")},
+			},
+			points:      map[string]int{"alice": 110},
+			wantHardest: 1,
+			want: map[string]PlayerSummary{
+				"alice": {
+					Points: 110, Questions: 2, Correct: 2, Accuracy: 1, AverageTimeMs: 1000, FastestTimeMs: 1000,
+					BestQuestion: 0,
+				},
+				"bob": {Questions: 2, BestQuestion: -1},
+			},
+		},
+		{
+			name: "unanswered question",
+			questions: [][]AnswerRecord{
+				{{PlayerKey: "alice"}},
+			},
+			wantHardest: 0,
+			want: map[string]PlayerSummary{
+				"alice": {Questions: 1, BestQuestion: -1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				room := newTestRoom(nil)
+				for index, answers := range tt.questions {
+					room.questionRecords = append(
+						room.questionRecords,
+						QuestionRecord{Number: index, Street: testStreet, Answers: answers},
+					)
+				}
+				summary := room.summarize(tt.points)
+				assert.Equal(t, 1, summary.Game)
+				assert.Equal(t, tt.wantHardest, summary.HardestQuestion)
+				require.Len(t, summary.Players, len(tt.want))
+				for index, player := range summary.Players {
+					want := tt.want[player.PlayerKey]
+					want.PlayerKey = player.PlayerKey
+					assert.Equal(t, want, player)
+					if index > 0 {
+						assert.GreaterOrEqual(t, summary.Players[index-1].Points, player.Points)
+					}
+				}
+			},
+		)
+	}
+}
+
+func TestRoom_SummaryAfterGame(t *testing.T) {
+	t.Parallel()
+	room := newTestRoom(nil)
+	alice, notifier := joinConnected(room, "Alice")
+	bob, _ := joinConnected(room, "Bob")
+	startGame(t, room, alice.Key)
+	answer(t, room, alice.Key, rightGuess)
+	answer(t, room, bob.Key, wrongGuess)
+	awaitAdvance(t, room)
+	ended := notifier.awaitEnd(t)
+	room.Lock()
+	summary, ok := room.Summary()
+	_, future := room.GameSummary(2)
+	room.Unlock()
+	require.True(t, ok)
+	assert.False(t, future)
+	assert.Equal(t, ended.summary, summary)
+	require.Len(t, summary.Players, 2)
+	assert.Equal(t, "Alice", summary.Players[0].Name)
+	assert.Equal(t, 1.0, summary.Players[0].Accuracy)
+	assert.Equal(t, "Bob", summary.Players[1].Name)
+	require.Len(t, summary.Questions, 1)
+	assert.Equal(t, testStreet, summary.Questions[0].Street)
+	assert.Greater(t, summary.Questions[0].Answers[0].DistanceMeters+summary.Questions[0].Answers[1].DistanceMeters, 0.0)
+}
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		name string
+		from types.Coordinate
+		to   types.Coordinate
+		want float64
+	}{
+		{name: "same point", from: rightGuess, to: rightGuess, want: 0},
+		{name: "one degree of latitude", from: types.Coordinate{Lat: 0, Lng: 0}, to: types.Coordinate{Lat: 1, Lng: 0}, want: 111195},
+		{name: "one degree of longitude at the equator", from: types.Coordinate{Lat: 0, Lng: 0}, to: types.Coordinate{Lat: 0, Lng: 1}, want: 111195},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				assert.InDelta(t, tt.want, distance(tt.from, tt.to), 1)
+			},
+		)
+	}
+}
+
+func TestRoom_GameSummary(t *testing.T) {
+	room := newTestRoom(nil)
+	room.summaries = []GameSummary{{Game: 1, HardestQuestion: 3}, {Game: 2, HardestQuestion: 5}}
+	tests := []struct {
+		name        string
+		game        int
+		wantFound   bool
+		wantHardest int
+	}{
+		{name: "first game", game: 1, wantFound: true, wantHardest: 3},
+		{name: "second game", game: 2, wantFound: true, wantHardest: 5},
+		{name: "unknown game", game: 3},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				summary, found := room.GameSummary(tt.game)
+				assert.Equal(t, tt.wantFound, found)
+				assert.Equal(t, tt.wantHardest, summary.HardestQuestion)
+			},
+		)
+	}
+	latest, ok := room.Summary()
+	assert.True(t, ok)
+	assert.Equal(t, 2, latest.Game)
+}
+
+func TestRoom_ExportToken(t *testing.T) {
+	first := newTestRoom(nil)
+	second := newTestRoom(nil)
+	assert.NotEmpty(t, first.ExportToken())
+	assert.NotEqual(t, first.ExportToken(), second.ExportToken())
+	_, ok := first.Summary()
+	assert.False(t, ok)
+}
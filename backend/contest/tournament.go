@@ -0,0 +1,241 @@
+package contest
+
+import (
+	"sort"
+	"time"
+
+	"github.com/fafeitsch/city-knowledge-contest/backend/geodata"
+)
+
+type Stage struct {
+	StreetList        *geodata.StreetList
+	NumberOfQuestions int
+	MaxAnswerTime     time.Duration
+	Eliminate         int
+}
+
+type TournamentStage struct {
+	Stage             int      `json:"stage"`
+	Stages            int      `json:"stages"`
+	ListName          string   `json:"listName"`
+	ListFileName      string   `json:"listFileName"`
+	NumberOfQuestions int      `json:"numberOfQuestions"`
+	MaxAnswerTimeSec  int      `json:"maxAnswerTimeSec"`
+	Eliminate         int      `json:"eliminate"`
+	Finished          bool     `json:"finished"`
+	Eliminated        []string `json:"eliminated"`
+}
+
+type TournamentStanding struct {
+	PlayerKey       string `json:"playerKey"`
+	Name            string `json:"name"`
+	Points          int    `json:"points"`
+	StagePoints     []int  `json:"stagePoints"`
+	Rank            int    `json:"rank"`
+	Eliminated      bool   `json:"eliminated"`
+	EliminatedAfter int    `json:"eliminatedAfter,omitempty"`
+	Forfeited       bool   `json:"forfeited,omitempty"`
+}
+
+type Tournament struct {
+	room       *Room
+	name       string
+	stages     []Stage
+	stage      int
+	completed  int
+	standings  map[string]*TournamentStanding
+	eliminated []string
+	archive    Archive
+}
+
+func NewTournament(room *Room, name string, stages []Stage, playerKey string) *Tournament {
+	tournament := &Tournament{
+		room:      room,
+		name:      name,
+		stages:    stages,
+		standings: make(map[string]*TournamentStanding),
+		archive:   room.archive,
+	}
+	room.archive = tournament
+	tournament.applyStage(playerKey)
+	return tournament
+}
+
+func (t *Tournament) Name() string {
+	return t.name
+}
+
+func (t *Tournament) Started() bool {
+	return t.stage > 0 || t.room.started
+}
+
+func (t *Tournament) Finished() bool {
+	return t.completed == len(t.stages)
+}
+
+func (t *Tournament) CanAdvance() bool {
+	return t.completed == t.stage+1 && t.stage+1 < len(t.stages) && t.room.finished
+}
+
+func (t *Tournament) Advance(playerKey string) {
+	t.stage = t.stage + 1
+	t.eliminated = nil
+	t.room.Rematch(playerKey, false)
+	t.applyStage(playerKey)
+}
+
+func (t *Tournament) Stage() TournamentStage {
+	stage := t.stages[t.stage]
+	result := TournamentStage{
+		Stage:             t.stage,
+		Stages:            len(t.stages),
+		NumberOfQuestions: stage.NumberOfQuestions,
+		MaxAnswerTimeSec:  int(stage.MaxAnswerTime.Seconds()),
+		Eliminate:         stage.Eliminate,
+		Finished:          t.completed > t.stage,
+		Eliminated:        make([]string, len(t.eliminated)),
+	}
+	copy(result.Eliminated, t.eliminated)
+	if stage.StreetList != nil {
+		result.ListName = stage.StreetList.Name
+		result.ListFileName = stage.StreetList.FileName
+	}
+	return result
+}
+
+func (t *Tournament) Standings() []TournamentStanding {
+	result := make([]TournamentStanding, 0, len(t.standings))
+	for _, standing := range t.standings {
+		copied := *standing
+		copied.StagePoints = make([]int, len(standing.StagePoints))
+		copy(copied.StagePoints, standing.StagePoints)
+		result = append(result, copied)
+	}
+	sort.Slice(
+		result, func(i, j int) bool {
+			if result[i].Forfeited != result[j].Forfeited {
+				return !result[i].Forfeited
+			}
+			if result[i].Eliminated != result[j].Eliminated {
+				return !result[i].Eliminated
+			}
+			if result[i].EliminatedAfter != result[j].EliminatedAfter {
+				return result[i].EliminatedAfter > result[j].EliminatedAfter
+			}
+			if result[i].Points != result[j].Points {
+				return result[i].Points > result[j].Points
+			}
+			return result[i].Name < result[j].Name
+		},
+	)
+	for index := range result {
+		result[index].Rank = index + 1
+		previous := index - 1
+		if previous >= 0 && result[previous].Points == result[index].Points &&
+			result[previous].Eliminated == result[index].Eliminated &&
+			result[previous].Forfeited == result[index].Forfeited &&
+			result[previous].EliminatedAfter == result[index].EliminatedAfter {
+			result[index].Rank = result[previous].Rank
+		}
+	}
+	return result
+}
+
+func (t *Tournament) Archive(record GameRecord) {
+	if t.archive != nil {
+		t.archive.Archive(record)
+	}
+	t.room.Lock()
+	defer t.room.Unlock()
+	if t.completed != t.stage {
+		return
+	}
+	points := make(map[string]int, len(record.Summary.Players))
+	for _, player := range record.Summary.Players {
+		points[player.PlayerKey] = player.Points
+	}
+	t.register()
+	for key, standing := range t.standings {
+		player, ok := t.room.players[key]
+		if ok {
+			standing.Name = player.Name
+		}
+		if !ok && !standing.Eliminated {
+			standing.Eliminated = true
+			standing.Forfeited = true
+			standing.EliminatedAfter = t.stage + 1
+			t.eliminated = append(t.eliminated, key)
+		}
+		if standing.Eliminated {
+			continue
+		}
+		standing.StagePoints = append(standing.StagePoints, points[key])
+		standing.Points = standing.Points + points[key]
+	}
+	t.completed = t.stage + 1
+	if !t.Finished() {
+		t.eliminate(t.stages[t.stage].Eliminate)
+	}
+	standings := t.Standings()
+	stage := t.Stage()
+	t.room.notifyPlayers(
+		func(player Player) {
+			player.NotifyTournamentStandings(standings)
+			player.NotifyTournamentStage(stage)
+		},
+	)
+}
+
+func (t *Tournament) eliminate(count int) {
+	remaining := make([]*TournamentStanding, 0, len(t.standings))
+	for _, standing := range t.standings {
+		if !standing.Eliminated {
+			remaining = append(remaining, standing)
+		}
+	}
+	if count >= len(remaining) {
+		count = len(remaining) - 1
+	}
+	sort.Slice(
+		remaining, func(i, j int) bool {
+			if remaining[i].Points != remaining[j].Points {
+				return remaining[i].Points < remaining[j].Points
+			}
+			return remaining[i].Name < remaining[j].Name
+		},
+	)
+	for index := 0; index < count; index++ {
+		standing := remaining[index]
+		standing.Eliminated = true
+		standing.EliminatedAfter = t.stage + 1
+		t.eliminated = append(t.eliminated, standing.PlayerKey)
+		t.room.ConvertToSpectator(standing.PlayerKey)
+	}
+}
+
+func (t *Tournament) register() {
+	for key, player := range t.room.players {
+		if _, ok := t.standings[key]; !ok {
+			t.standings[key] = &TournamentStanding{PlayerKey: key, Name: player.Name, StagePoints: make([]int, t.stage)}
+		}
+	}
+}
+
+func (t *Tournament) applyStage(playerKey string) {
+	t.register()
+	stage := t.stages[t.stage]
+	options := t.room.options
+	options.Name = t.name
+	options.Mode = ClassicMode
+	options.StreetList = stage.StreetList
+	options.NumberOfQuestions = stage.NumberOfQuestions
+	options.MaxAnswerTime = stage.MaxAnswerTime
+	options.RefuseLateJoin = true
+	t.room.SetOptions(options, playerKey)
+	current := t.Stage()
+	t.room.notifyPlayers(
+		func(player Player) {
+			player.NotifyTournamentStage(current)
+		},
+	)
+}
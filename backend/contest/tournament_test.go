@@ -0,0 +1,206 @@
+package contest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fafeitsch/city-knowledge-contest/backend/geodata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTournament(t *testing.T, names []string, stages ...Stage) (*Tournament, map[string]string) {
+	room := newTestRoom(nil)
+	keys := make(map[string]string)
+	for _, name := range names {
+		player, _ := joinConnected(room, name)
+		keys[name] = player.Key
+	}
+	room.Lock()
+	defer room.Unlock()
+	tournament := NewTournament(room, "Cup", stages, keys[names[0]])
+	require.Equal(t, "Cup", tournament.Name())
+	require.False(t, tournament.Started())
+	return tournament, keys
+}
+
+func testStage(eliminate int) Stage {
+	return Stage{
+		StreetList:        &geodata.StreetList{FileName: "test.json", Name: "Test"},
+		NumberOfQuestions: 1,
+		MaxAnswerTime:     10 * time.Second,
+		Eliminate:         eliminate,
+	}
+}
+
+func stageRecord(keys map[string]string, points map[string]int) GameRecord {
+	record := GameRecord{}
+	for name, value := range points {
+		record.Summary.Players = append(record.Summary.Players, PlayerSummary{PlayerKey: keys[name], Name: name, Points: value})
+	}
+	return record
+}
+
+func TestTournament_Archive(t *testing.T) {
+	tests := []struct {
+		name           string
+		eliminate      int
+		points         map[string]int
+		left           []string
+		wantEliminated []string
+		wantForfeited  []string
+		wantRanks      map[string]int
+	}{
+		{
+			name:           "lowest player is eliminated",
+			eliminate:      1,
+			points:         map[string]int{"Alice": 100, "Bob": 50, "Carol": 10},
+			wantEliminated: []string{"Carol"},
+			wantRanks:      map[string]int{"Alice": 1, "Bob": 2, "Carol": 3},
+		},
+		{
+			name:           "ties are eliminated by name",
+			eliminate:      1,
+			points:         map[string]int{"Alice": 50, "Bob": 50, "Carol": 50},
+			wantEliminated: []string{"Alice"},
+			wantRanks:      map[string]int{"Bob": 1, "Carol": 1, "Alice": 3},
+		},
+		{
+			name:           "one player always remains",
+			eliminate:      5,
+			points:         map[string]int{"Alice": 100, "Bob": 50, "Carol": 10},
+			wantEliminated: []string{"Carol", "Bob"},
+			wantRanks:      map[string]int{"Alice": 1, "Bob": 2, "Carol": 3},
+		},
+		{
+			name:           "player who left forfeits",
+			eliminate:      1,
+			points:         map[string]int{"Alice": 100, "Bob": 50},
+			left:           []string{"Carol"},
+			wantEliminated: []string{"Carol", "Bob"},
+			wantForfeited:  []string{"Carol"},
+			wantRanks:      map[string]int{"Alice": 1, "Bob": 2, "Carol": 3},
+		},
+		{
+			name:           "forfeit without further eliminations",
+			points:         map[string]int{"Alice": 100, "Bob": 50},
+			left:           []string{"Carol"},
+			wantEliminated: []string{"Carol"},
+			wantForfeited:  []string{"Carol"},
+			wantRanks:      map[string]int{"Alice": 1, "Bob": 2, "Carol": 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				tournament, keys := newTestTournament(t, []string{"Alice", "Bob", "Carol"}, testStage(tt.eliminate), testStage(0))
+				names := make(map[string]string)
+				for name, key := range keys {
+					names[key] = name
+				}
+				room := tournament.room
+				room.Lock()
+				for _, name := range tt.left {
+					room.Leave(keys[name])
+				}
+				room.Unlock()
+				tournament.Archive(stageRecord(keys, tt.points))
+
+				room.Lock()
+				defer room.Unlock()
+				stage := tournament.Stage()
+				eliminated := make([]string, 0)
+				for _, key := range stage.Eliminated {
+					eliminated = append(eliminated, names[key])
+				}
+				assert.Equal(t, tt.wantEliminated, eliminated)
+				assert.True(t, stage.Finished)
+				assert.False(t, tournament.Finished())
+				forfeited := make([]string, 0)
+				for _, standing := range tournament.Standings() {
+					assert.Equal(t, tt.wantRanks[standing.Name], standing.Rank, standing.Name)
+					assert.Equal(t, tt.points[standing.Name], standing.Points, standing.Name)
+					if standing.Forfeited {
+						forfeited = append(forfeited, standing.Name)
+						assert.True(t, standing.Eliminated)
+					}
+					if standing.Eliminated {
+						assert.Equal(t, 1, standing.EliminatedAfter, standing.Name)
+					}
+				}
+				assert.ElementsMatch(t, tt.wantForfeited, forfeited)
+				for _, name := range tt.wantEliminated {
+					_, stillPlaying := room.FindPlayer(keys[name])
+					assert.False(t, stillPlaying, name)
+				}
+			},
+		)
+	}
+}
+
+func TestTournament_Advance(t *testing.T) {
+	tournament, keys := newTestTournament(t, []string{"Alice", "Bob", "Carol"}, testStage(1), testStage(0))
+	room := tournament.room
+	room.Lock()
+	room.started = true
+	room.finished = true
+	room.Unlock()
+	tournament.Archive(stageRecord(keys, map[string]int{"Alice": 100, "Bob": 50, "Carol": 10}))
+	tournament.Archive(stageRecord(keys, map[string]int{"Alice": 0, "Bob": 0, "Carol": 0}))
+
+	room.Lock()
+	require.True(t, tournament.CanAdvance())
+	tournament.Advance(keys["Alice"])
+	assert.False(t, tournament.CanAdvance())
+	assert.True(t, tournament.Started())
+	assert.Equal(t, 1, tournament.Stage().Stage)
+	assert.Empty(t, tournament.Stage().Eliminated)
+	assert.True(t, room.Options().RefuseLateJoin)
+	room.Unlock()
+
+	tournament.Archive(stageRecord(keys, map[string]int{"Alice": 10, "Bob": 70}))
+	room.Lock()
+	defer room.Unlock()
+	assert.True(t, tournament.Finished())
+	assert.Empty(t, tournament.Stage().Eliminated)
+	standings := tournament.Standings()
+	require.Len(t, standings, 3)
+	assert.Equal(t, "Bob", standings[0].Name)
+	assert.Equal(t, []int{50, 70}, standings[0].StagePoints)
+	assert.Equal(t, "Alice", standings[1].Name)
+	assert.Equal(t, 110, standings[1].Points)
+	assert.Equal(t, "Carol", standings[2].Name)
+	assert.Equal(t, []int{10}, standings[2].StagePoints)
+}
+
+func TestTournament_ArchiveForfeit(t *testing.T) {
+	tournament, keys := newTestTournament(t, []string{"Alice", "Bob", "Carol"}, testStage(0), testStage(1), testStage(0))
+	room := tournament.room
+	room.Lock()
+	room.started = true
+	room.finished = true
+	room.Unlock()
+	tournament.Archive(stageRecord(keys, map[string]int{"Alice": 100, "Bob": 50, "Carol": 10}))
+
+	room.Lock()
+	tournament.Advance(keys["Alice"])
+	room.Leave(keys["Carol"])
+	room.finished = true
+	room.Unlock()
+	tournament.Archive(stageRecord(keys, map[string]int{"Alice": 10, "Bob": 20}))
+
+	room.Lock()
+	defer room.Unlock()
+	assert.Equal(t, []string{keys["Carol"], keys["Bob"]}, tournament.Stage().Eliminated)
+	standings := tournament.Standings()
+	require.Len(t, standings, 3)
+	assert.Equal(t, "Alice", standings[0].Name)
+	assert.False(t, standings[0].Eliminated)
+	assert.Equal(t, "Bob", standings[1].Name)
+	assert.False(t, standings[1].Forfeited)
+	assert.Equal(t, 2, standings[1].EliminatedAfter)
+	assert.Equal(t, "Carol", standings[2].Name)
+	assert.True(t, standings[2].Forfeited)
+	assert.Equal(t, 2, standings[2].EliminatedAfter)
+	assert.Equal(t, []int{10}, standings[2].StagePoints)
+}
@@ -6,6 +6,7 @@ require (
 	github.com/jaevor/go-nanoid v1.3.0
 	github.com/stretchr/testify v1.8.0
 	github.com/urfave/cli/v2 v2.23.7
+	golang.org/x/crypto v0.10.0
 	nhooyr.io/websocket v1.8.7
 )
 
@@ -0,0 +1,124 @@
+package history
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+type Filter struct {
+	Profile    string
+	StreetList string
+	From       time.Time
+	To         time.Time
+}
+
+type LeaderboardEntry struct {
+	Name          string  `json:"name"`
+	ProfileId     string  `json:"profileId,omitempty"`
+	Games         int     `json:"games"`
+	Wins          int     `json:"wins"`
+	TotalPoints   int     `json:"totalPoints"`
+	BestPoints    int     `json:"bestPoints"`
+	AveragePoints float64 `json:"averagePoints"`
+	Accuracy      float64 `json:"accuracy"`
+}
+
+func (f Filter) matches(game Game) bool {
+	if f.Profile != "" && game.player(f.Profile) == nil {
+		return false
+	}
+	if f.StreetList != "" && f.StreetList != game.StreetList {
+		return false
+	}
+	if !f.From.IsZero() && game.Finished.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && game.Finished.After(f.To) {
+		return false
+	}
+	return true
+}
+
+func (s *Store) Games(filter Filter) []Game {
+	s.RLock()
+	defer s.RUnlock()
+	result := make([]Game, 0)
+	for _, game := range s.games {
+		if filter.matches(game) {
+			result = append(result, game)
+		}
+	}
+	return result
+}
+
+func (s *Store) Leaderboard(filter Filter, limit int) []LeaderboardEntry {
+	entries := make(map[string]*LeaderboardEntry)
+	correct := make(map[string]int)
+	questions := make(map[string]int)
+	for _, game := range s.Games(filter) {
+		for _, player := range game.Players {
+			identity := player.identity()
+			entry, ok := entries[identity]
+			if !ok {
+				entry = &LeaderboardEntry{Name: player.Name, ProfileId: player.ProfileId}
+				entries[identity] = entry
+			}
+			entry.Games = entry.Games + 1
+			entry.TotalPoints = entry.TotalPoints + player.Points
+			if player.Points > entry.BestPoints {
+				entry.BestPoints = player.Points
+			}
+			if player.Rank == 1 && len(game.Players) > 1 {
+				entry.Wins = entry.Wins + 1
+			}
+			correct[identity] = correct[identity] + player.Correct
+			questions[identity] = questions[identity] + player.Questions
+		}
+	}
+	result := make([]LeaderboardEntry, 0, len(entries))
+	for identity, entry := range entries {
+		entry.AveragePoints = float64(entry.TotalPoints) / float64(entry.Games)
+		if questions[identity] > 0 {
+			entry.Accuracy = float64(correct[identity]) / float64(questions[identity])
+		}
+		result = append(result, *entry)
+	}
+	sort.Slice(
+		result, func(i, j int) bool {
+			if result[i].TotalPoints != result[j].TotalPoints {
+				return result[i].TotalPoints > result[j].TotalPoints
+			}
+			return result[i].Name < result[j].Name
+		},
+	)
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+func (s *Store) Statistics(profile string) LeaderboardEntry {
+	for _, entry := range s.Leaderboard(Filter{Profile: profile}, 0) {
+		if entry.ProfileId == profile {
+			return entry
+		}
+	}
+	return LeaderboardEntry{ProfileId: profile}
+}
+
+func (g Game) player(profile string) *PlayerResult {
+	for index, player := range g.Players {
+		if player.ProfileId == profile {
+			return &g.Players[index]
+		}
+	}
+	return nil
+}
+
+func (p PlayerResult) identity() string {
+	if p.ProfileId != "" {
+		return "profile:" + p.ProfileId
+	}
+	return "name:" + strings.ToLower(strings.TrimSpace(p.Name))
+}
@@ -0,0 +1,122 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fafeitsch/city-knowledge-contest/backend/contest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_Leaderboard(t *testing.T) {
+	january := time.Date(2022, 1, 15, 20, 0, 0, 0, time.UTC)
+	march := time.Date(2022, 3, 15, 20, 0, 0, 0, time.UTC)
+	store, err := Open("")
+	require.NoError(t, err)
+	store.Archive(
+		record(
+			"a", january,
+			contest.PlayerSummary{Name: "Alice", ProfileId: "alice", Points: 100, Correct: 4, Questions: 5},
+			contest.PlayerSummary{Name: "bob ", Points: 40, Correct: 2, Questions: 5},
+		),
+	)
+	store.Archive(
+		record(
+			"b", march,
+			contest.PlayerSummary{Name: "Bob", Points: 80, Correct: 3, Questions: 5},
+			contest.PlayerSummary{Name: "Alicia", ProfileId: "alice", Points: 20, Correct: 1, Questions: 5},
+		),
+	)
+	other := record("c", march, contest.PlayerSummary{Name: "Carol", Points: 500, Correct: 5, Questions: 5})
+	other.Options.StreetList.FileName = "berlin.json"
+	store.Archive(other)
+
+	tests := []struct {
+		name   string
+		filter Filter
+		limit  int
+		want   []LeaderboardEntry
+	}{
+		{
+			name:   "all time for one street list",
+			filter: Filter{StreetList: "wuerzburg.json"},
+			want: []LeaderboardEntry{
+				{
+					Name: "Alice", ProfileId: "alice", Games: 2, Wins: 1, TotalPoints: 120, BestPoints: 100,
+					AveragePoints: 60, Accuracy: 0.5,
+				},
+				{
+					Name: "bob ", Games: 2, Wins: 1, TotalPoints: 120, BestPoints: 80, AveragePoints: 60,
+					Accuracy: 0.5,
+				},
+			},
+		},
+		{
+			name:   "time range",
+			filter: Filter{From: march.Add(-time.Hour), To: march.Add(time.Hour)},
+			want: []LeaderboardEntry{
+				{Name: "Carol", Games: 1, TotalPoints: 500, BestPoints: 500, AveragePoints: 500, Accuracy: 1},
+				{Name: "Bob", Games: 1, Wins: 1, TotalPoints: 80, BestPoints: 80, AveragePoints: 80, Accuracy: 0.6},
+				{
+					Name: "Alicia", ProfileId: "alice", Games: 1, TotalPoints: 20, BestPoints: 20, AveragePoints: 20,
+					Accuracy: 0.2,
+				},
+			},
+		},
+		{
+			name:   "limited",
+			filter: Filter{},
+			limit:  1,
+			want: []LeaderboardEntry{
+				{Name: "Carol", Games: 1, TotalPoints: 500, BestPoints: 500, AveragePoints: 500, Accuracy: 1},
+			},
+		},
+		{
+			name:   "nothing in range",
+			filter: Filter{To: january.Add(-time.Hour)},
+			want:   []LeaderboardEntry{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				assert.Equal(t, tt.want, store.Leaderboard(tt.filter, tt.limit))
+			},
+		)
+	}
+}
+
+func TestStore_Statistics(t *testing.T) {
+	store, err := Open("")
+	require.NoError(t, err)
+	store.Archive(
+		record(
+			"a", time.Now(),
+			contest.PlayerSummary{Name: "Alice", ProfileId: "alice", Points: 70, Correct: 3, Questions: 4},
+			contest.PlayerSummary{Name: "Bob", ProfileId: "bob", Points: 30, Correct: 1, Questions: 4},
+		),
+	)
+	tests := []struct {
+		name    string
+		profile string
+		want    LeaderboardEntry
+	}{
+		{
+			name:    "known profile",
+			profile: "bob",
+			want: LeaderboardEntry{
+				Name: "Bob", ProfileId: "bob", Games: 1, TotalPoints: 30, BestPoints: 30, AveragePoints: 30,
+				Accuracy: 0.25,
+			},
+		},
+		{name: "unknown profile", profile: "carol", want: LeaderboardEntry{ProfileId: "carol"}},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				assert.Equal(t, tt.want, store.Statistics(tt.profile))
+			},
+		)
+	}
+}
@@ -0,0 +1,114 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/fafeitsch/city-knowledge-contest/backend/contest"
+)
+
+type PlayerResult struct {
+	PlayerKey string `json:"playerKey"`
+	Name      string `json:"name"`
+	ProfileId string `json:"profileId,omitempty"`
+	Points    int    `json:"points"`
+	Rank      int    `json:"rank"`
+	Correct   int    `json:"correct"`
+	Questions int    `json:"questions"`
+}
+
+type Game struct {
+	RoomKey        string         `json:"roomKey"`
+	Game           int            `json:"game"`
+	StreetList     string         `json:"streetList"`
+	StreetListName string         `json:"streetListName"`
+	Mode           string         `json:"mode"`
+	Finished       time.Time      `json:"finished"`
+	Players        []PlayerResult `json:"players"`
+}
+
+type Store struct {
+	sync.RWMutex
+	file  *os.File
+	games []Game
+}
+
+func Open(path string) (*Store, error) {
+	store := &Store{}
+	if path == "" {
+		return store, nil
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("could not open history file \"%s\": %v", path, err)
+	}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var game Game
+		if err := json.Unmarshal(scanner.Bytes(), &game); err != nil {
+			log.Printf("skipping unreadable entry in history file \"%s\": %v", path, err)
+			continue
+		}
+		store.games = append(store.games, game)
+	}
+	if err := scanner.Err(); err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("could not read history file \"%s\": %v", path, err)
+	}
+	store.file = file
+	return store, nil
+}
+
+func (s *Store) Close() error {
+	if s.file == nil {
+		return nil
+	}
+	return s.file.Close()
+}
+
+func (s *Store) Archive(record contest.GameRecord) {
+	game := Game{
+		RoomKey:  record.RoomKey,
+		Game:     record.Summary.Game,
+		Mode:     string(record.Options.Mode),
+		Finished: record.Finished,
+		Players:  make([]PlayerResult, 0, len(record.Summary.Players)),
+	}
+	if record.Options.StreetList != nil {
+		game.StreetList = record.Options.StreetList.FileName
+		game.StreetListName = record.Options.StreetList.Name
+	}
+	for index, player := range record.Summary.Players {
+		rank := index + 1
+		if index > 0 && player.Points == record.Summary.Players[index-1].Points {
+			rank = game.Players[index-1].Rank
+		}
+		game.Players = append(
+			game.Players, PlayerResult{
+				PlayerKey: player.PlayerKey,
+				Name:      player.Name,
+				ProfileId: player.ProfileId,
+				Points:    player.Points,
+				Rank:      rank,
+				Correct:   player.Correct,
+				Questions: player.Questions,
+			},
+		)
+	}
+	s.Lock()
+	defer s.Unlock()
+	s.games = append(s.games, game)
+	if s.file == nil {
+		return
+	}
+	line, _ := json.Marshal(game)
+	if _, err := s.file.Write(append(line, '\n')); err != nil {
+		log.Printf("could not write game of room \"%s\" to history: %v", game.RoomKey, err)
+	}
+}
@@ -0,0 +1,99 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fafeitsch/city-knowledge-contest/backend/contest"
+	"github.com/fafeitsch/city-knowledge-contest/backend/geodata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func record(roomKey string, finished time.Time, players ...contest.PlayerSummary) contest.GameRecord {
+	return contest.GameRecord{
+		RoomKey: roomKey,
+		Options: contest.RoomOptions{
+			Mode:       contest.ClassicMode,
+			StreetList: &geodata.StreetList{FileName: "wuerzburg.json", Name: "Würzburg"},
+		},
+		Summary:  contest.GameSummary{Game: 1, Players: players},
+		Finished: finished,
+	}
+}
+
+func TestStore_Archive(t *testing.T) {
+	tests := []struct {
+		name      string
+		players   []contest.PlayerSummary
+		wantRanks []int
+	}{
+		{name: "no players", wantRanks: []int{}},
+		{
+			name: "distinct points",
+			players: []contest.PlayerSummary{
+				{Name: "Alice", Points: 90}, {Name: "Bob", Points: 60}, {Name: "Carol", Points: 10},
+			},
+			wantRanks: []int{1, 2, 3},
+		},
+		{
+			name: "shared rank",
+			players: []contest.PlayerSummary{
+				{Name: "Alice", Points: 90}, {Name: "Bob", Points: 90}, {Name: "Carol", Points: 10},
+			},
+			wantRanks: []int{1, 1, 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				store, err := Open("")
+				require.NoError(t, err)
+				store.Archive(record("room", time.Now(), tt.players...))
+				games := store.Games(Filter{})
+				require.Len(t, games, 1)
+				assert.Equal(t, "wuerzburg.json", games[0].StreetList)
+				assert.Equal(t, "Würzburg", games[0].StreetListName)
+				assert.Equal(t, string(contest.ClassicMode), games[0].Mode)
+				ranks := make([]int, 0)
+				for _, player := range games[0].Players {
+					ranks = append(ranks, player.Rank)
+				}
+				assert.Equal(t, tt.wantRanks, ranks)
+				assert.NoError(t, store.Close())
+			},
+		)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	store, err := Open(path)
+	require.NoError(t, err)
+	finished := time.Date(2022, 5, 1, 18, 0, 0, 0, time.UTC)
+	store.Archive(record("first", finished, contest.PlayerSummary{Name: "Alice", Points: 50}))
+	store.Archive(record("second", finished, contest.PlayerSummary{Name: "Bob", Points: 20}))
+	require.NoError(t, store.Close())
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	require.NoError(t, err)
+	_, err = file.WriteString("not json\n")
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	reopened, err := Open(path)
+	require.NoError(t, err)
+	defer func() { _ = reopened.Close() }()
+	games := reopened.Games(Filter{})
+	require.Len(t, games, 2)
+	assert.Equal(t, "first", games[0].RoomKey)
+	assert.True(t, finished.Equal(games[0].Finished))
+	assert.Equal(t, "Bob", games[1].Players[0].Name)
+}
+
+func TestOpen_InvalidPath(t *testing.T) {
+	_, err := Open(filepath.Join(t.TempDir(), "missing", "history.jsonl"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,174 @@
+package profile
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fafeitsch/city-knowledge-contest/backend/keygen"
+)
+
+const maxNicknameLength = 50
+
+type Profile struct {
+	Id       string    `json:"id"`
+	Nickname string    `json:"nickname"`
+	Created  time.Time `json:"created"`
+}
+
+type storedProfile struct {
+	Profile
+	TokenHash string `json:"tokenHash"`
+}
+
+type Registry struct {
+	sync.RWMutex
+	path     string
+	profiles map[string]*storedProfile
+	tokens   map[string]*storedProfile
+}
+
+func Open(path string) (*Registry, error) {
+	registry := &Registry{
+		path:     path,
+		profiles: make(map[string]*storedProfile),
+		tokens:   make(map[string]*storedProfile),
+	}
+	if path == "" {
+		return registry, nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return registry, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read profile file \"%s\": %v", path, err)
+	}
+	stored := make([]*storedProfile, 0)
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("could not parse profile file \"%s\": %v", path, err)
+	}
+	for _, profile := range stored {
+		registry.profiles[profile.Id] = profile
+		registry.tokens[profile.TokenHash] = profile
+	}
+	return registry, nil
+}
+
+func ValidateNickname(nickname string) error {
+	nickname = strings.TrimSpace(nickname)
+	if len(nickname) == 0 {
+		return fmt.Errorf("a nickname must not be empty")
+	}
+	if len(nickname) > maxNicknameLength {
+		return fmt.Errorf("a nickname must not be longer than %d characters", maxNicknameLength)
+	}
+	return nil
+}
+
+func (r *Registry) Create(nickname string) (Profile, string, error) {
+	if err := ValidateNickname(nickname); err != nil {
+		return Profile{}, "", err
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return Profile{}, "", fmt.Errorf("could not generate profile token: %v", err)
+	}
+	token := hex.EncodeToString(secret)
+	profile := &storedProfile{
+		Profile: Profile{
+			Id:       keygen.PlayerKey(),
+			Nickname: strings.TrimSpace(nickname),
+			Created:  time.Now(),
+		},
+		TokenHash: hashToken(token),
+	}
+	r.Lock()
+	defer r.Unlock()
+	r.profiles[profile.Id] = profile
+	r.tokens[profile.TokenHash] = profile
+	return profile.Profile, token, r.save()
+}
+
+func (r *Registry) Authenticate(token string) (Profile, bool) {
+	if token == "" {
+		return Profile{}, false
+	}
+	r.RLock()
+	defer r.RUnlock()
+	profile, ok := r.tokens[hashToken(token)]
+	if !ok {
+		return Profile{}, false
+	}
+	return profile.Profile, true
+}
+
+func (r *Registry) Get(id string) (Profile, bool) {
+	r.RLock()
+	defer r.RUnlock()
+	profile, ok := r.profiles[id]
+	if !ok {
+		return Profile{}, false
+	}
+	return profile.Profile, true
+}
+
+func (r *Registry) Rename(token string, nickname string) (Profile, error) {
+	if err := ValidateNickname(nickname); err != nil {
+		return Profile{}, err
+	}
+	r.Lock()
+	defer r.Unlock()
+	profile, ok := r.tokens[hashToken(token)]
+	if !ok {
+		return Profile{}, fmt.Errorf("profile not found")
+	}
+	profile.Nickname = strings.TrimSpace(nickname)
+	return profile.Profile, r.save()
+}
+
+func (r *Registry) Delete(token string) error {
+	r.Lock()
+	defer r.Unlock()
+	hash := hashToken(token)
+	profile, ok := r.tokens[hash]
+	if !ok {
+		return fmt.Errorf("profile not found")
+	}
+	delete(r.tokens, hash)
+	delete(r.profiles, profile.Id)
+	return r.save()
+}
+
+func (r *Registry) save() error {
+	if r.path == "" {
+		return nil
+	}
+	stored := make([]*storedProfile, 0, len(r.profiles))
+	for _, profile := range r.profiles {
+		stored = append(stored, profile)
+	}
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return fmt.Errorf("could not serialize profiles: %v", err)
+	}
+	temporary := r.path + ".tmp"
+	if err := os.WriteFile(temporary, data, 0600); err != nil {
+		return fmt.Errorf("could not write profile file \"%s\": %v", r.path, err)
+	}
+	if err := os.Rename(temporary, r.path); err != nil {
+		return fmt.Errorf("could not write profile file \"%s\": %v", r.path, err)
+	}
+	return nil
+}
+
+func hashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
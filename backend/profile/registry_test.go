@@ -0,0 +1,85 @@
+package profile
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateNickname(t *testing.T) {
+	tests := []struct {
+		name     string
+		nickname string
+		wantErr  bool
+	}{
+		{name: "valid", nickname: "Alice"},
+		{name: "empty", nickname: "", wantErr: true},
+		{name: "whitespace only", nickname: "   ", wantErr: true},
+		{name: "maximum length", nickname: strings.Repeat("a", maxNicknameLength)},
+		{name: "too long", nickname: strings.Repeat("a", maxNicknameLength+1), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				err := ValidateNickname(tt.nickname)
+				assert.Equal(t, tt.wantErr, err != nil)
+			},
+		)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.json")
+	registry, err := Open(path)
+	require.NoError(t, err)
+	created, token, err := registry.Create("  Alice ")
+	require.NoError(t, err)
+	assert.Equal(t, "Alice", created.Nickname)
+	assert.NotEmpty(t, token)
+
+	reopened, err := Open(path)
+	require.NoError(t, err)
+	tests := []struct {
+		name   string
+		token  string
+		wantOk bool
+	}{
+		{name: "valid token", token: token, wantOk: true},
+		{name: "empty token"},
+		{name: "unknown token", token: "unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				profile, ok := reopened.Authenticate(tt.token)
+				assert.Equal(t, tt.wantOk, ok)
+				if tt.wantOk {
+					assert.Equal(t, created.Id, profile.Id)
+				}
+			},
+		)
+	}
+
+	renamed, err := reopened.Rename(token, "Alicia")
+	require.NoError(t, err)
+	assert.Equal(t, "Alicia", renamed.Nickname)
+	_, err = reopened.Rename("unknown", "Mallory")
+	assert.Error(t, err)
+	_, err = reopened.Rename(token, "")
+	assert.Error(t, err)
+	found, ok := reopened.Get(created.Id)
+	require.True(t, ok)
+	assert.Equal(t, "Alicia", found.Nickname)
+
+	require.NoError(t, reopened.Delete(token))
+	assert.Error(t, reopened.Delete(token))
+	_, ok = reopened.Get(created.Id)
+	assert.False(t, ok)
+	empty, err := Open(path)
+	require.NoError(t, err)
+	_, ok = empty.Authenticate(token)
+	assert.False(t, ok)
+}
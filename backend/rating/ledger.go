@@ -0,0 +1,194 @@
+package rating
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fafeitsch/city-knowledge-contest/backend/contest"
+)
+
+const (
+	InitialRating     = 1500
+	provisionalGames  = 10
+	provisionalFactor = 40.0
+	establishedFactor = 20.0
+)
+
+type Entry struct {
+	ProfileId  string    `json:"profileId"`
+	StreetList string    `json:"streetList"`
+	Rating     int       `json:"rating"`
+	Delta      int       `json:"delta"`
+	Games      int       `json:"games"`
+	Time       time.Time `json:"time"`
+}
+
+type Rating struct {
+	StreetList string  `json:"streetList"`
+	Rating     int     `json:"rating"`
+	Games      int     `json:"games"`
+	History    []Entry `json:"history"`
+}
+
+type key struct {
+	profile    string
+	streetList string
+}
+
+type Ledger struct {
+	sync.RWMutex
+	file    *os.File
+	current map[key]Entry
+	entries []Entry
+}
+
+func Open(path string) (*Ledger, error) {
+	ledger := &Ledger{current: make(map[key]Entry)}
+	if path == "" {
+		return ledger, nil
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("could not open rating file \"%s\": %v", path, err)
+	}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			log.Printf("skipping unreadable entry in rating file \"%s\": %v", path, err)
+			continue
+		}
+		ledger.add(entry)
+	}
+	if err := scanner.Err(); err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("could not read rating file \"%s\": %v", path, err)
+	}
+	ledger.file = file
+	return ledger, nil
+}
+
+func (l *Ledger) Close() error {
+	if l.file == nil {
+		return nil
+	}
+	return l.file.Close()
+}
+
+func (l *Ledger) Rate(streetList string, points map[string]int) map[string]contest.RatingChange {
+	l.Lock()
+	defer l.Unlock()
+	before := make(map[string]Entry, len(points))
+	for profile := range points {
+		before[profile] = l.rating(profile, streetList)
+	}
+	opponents := float64(len(points) - 1)
+	now := time.Now()
+	result := make(map[string]contest.RatingChange, len(points))
+	for profile, own := range points {
+		rating := float64(before[profile].Rating)
+		delta := 0.0
+		for opponent, other := range points {
+			if opponent == profile {
+				continue
+			}
+			expected := 1 / (1 + math.Pow(10, (float64(before[opponent].Rating)-rating)/400))
+			delta = delta + score(own, other) - expected
+		}
+		change := int(math.Round(factor(before[profile].Games) * delta / opponents))
+		entry := Entry{
+			ProfileId:  profile,
+			StreetList: streetList,
+			Rating:     before[profile].Rating + change,
+			Delta:      change,
+			Games:      before[profile].Games + 1,
+			Time:       now,
+		}
+		l.add(entry)
+		l.write(entry)
+		result[profile] = contest.RatingChange{
+			ProfileId: profile,
+			Before:    before[profile].Rating,
+			After:     entry.Rating,
+			Delta:     change,
+			Games:     entry.Games,
+		}
+	}
+	return result
+}
+
+func (l *Ledger) Ratings(profile string, streetList string) []Rating {
+	l.RLock()
+	defer l.RUnlock()
+	ratings := make(map[string]*Rating)
+	for _, entry := range l.entries {
+		if entry.ProfileId != profile || (streetList != "" && entry.StreetList != streetList) {
+			continue
+		}
+		rating, ok := ratings[entry.StreetList]
+		if !ok {
+			rating = &Rating{StreetList: entry.StreetList, History: make([]Entry, 0)}
+			ratings[entry.StreetList] = rating
+		}
+		rating.Rating = entry.Rating
+		rating.Games = entry.Games
+		rating.History = append(rating.History, entry)
+	}
+	result := make([]Rating, 0, len(ratings))
+	for _, rating := range ratings {
+		result = append(result, *rating)
+	}
+	sort.Slice(
+		result, func(i, j int) bool {
+			return result[i].StreetList < result[j].StreetList
+		},
+	)
+	return result
+}
+
+func (l *Ledger) rating(profile string, streetList string) Entry {
+	entry, ok := l.current[key{profile: profile, streetList: streetList}]
+	if !ok {
+		return Entry{ProfileId: profile, StreetList: streetList, Rating: InitialRating}
+	}
+	return entry
+}
+
+func (l *Ledger) add(entry Entry) {
+	l.current[key{profile: entry.ProfileId, streetList: entry.StreetList}] = entry
+	l.entries = append(l.entries, entry)
+}
+
+func (l *Ledger) write(entry Entry) {
+	if l.file == nil {
+		return
+	}
+	line, _ := json.Marshal(entry)
+	if _, err := l.file.Write(append(line, '\n')); err != nil {
+		log.Printf("could not write rating of profile \"%s\": %v", entry.ProfileId, err)
+	}
+}
+
+func score(own int, other int) float64 {
+	if own > other {
+		return 1
+	}
+	if own < other {
+		return 0
+	}
+	return 0.5
+}
+
+func factor(games int) float64 {
+	if games < provisionalGames {
+		return provisionalFactor
+	}
+	return establishedFactor
+}
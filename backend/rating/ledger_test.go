@@ -0,0 +1,122 @@
+package rating
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLedger_Rate(t *testing.T) {
+	tests := []struct {
+		name      string
+		games     map[string]int
+		points    map[string]int
+		wantDelta map[string]int
+	}{
+		{
+			name:      "winner and loser",
+			points:    map[string]int{"alice": 100, "bob": 50},
+			wantDelta: map[string]int{"alice": 20, "bob": -20},
+		},
+		{
+			name:      "draw",
+			points:    map[string]int{"alice": 70, "bob": 70},
+			wantDelta: map[string]int{"alice": 0, "bob": 0},
+		},
+		{
+			name:      "three players",
+			points:    map[string]int{"alice": 100, "bob": 50, "carol": 0},
+			wantDelta: map[string]int{"alice": 20, "bob": 0, "carol": -20},
+		},
+		{
+			name:      "established players",
+			games:     map[string]int{"alice": provisionalGames, "bob": provisionalGames},
+			points:    map[string]int{"alice": 100, "bob": 50},
+			wantDelta: map[string]int{"alice": 10, "bob": -10},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				ledger, err := Open("")
+				require.NoError(t, err)
+				for profile, games := range tt.games {
+					ledger.add(Entry{ProfileId: profile, StreetList: "list", Rating: InitialRating, Games: games})
+				}
+				changes := ledger.Rate("list", tt.points)
+				require.Len(t, changes, len(tt.points))
+				for profile, change := range changes {
+					assert.Equal(t, profile, change.ProfileId)
+					assert.Equal(t, InitialRating, change.Before, profile)
+					assert.Equal(t, tt.wantDelta[profile], change.Delta, profile)
+					assert.Equal(t, InitialRating+tt.wantDelta[profile], change.After, profile)
+					assert.Equal(t, tt.games[profile]+1, change.Games, profile)
+				}
+			},
+		)
+	}
+}
+
+func TestLedger_RateUnevenOpponents(t *testing.T) {
+	ledger, err := Open("")
+	require.NoError(t, err)
+	ledger.add(Entry{ProfileId: "alice", StreetList: "list", Rating: 1900, Games: 20})
+	changes := ledger.Rate("list", map[string]int{"alice": 100, "bob": 50})
+	assert.Equal(t, 2, changes["alice"].Delta)
+	assert.Equal(t, -4, changes["bob"].Delta)
+}
+
+func TestLedger_Ratings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ratings.jsonl")
+	ledger, err := Open(path)
+	require.NoError(t, err)
+	ledger.Rate("wuerzburg", map[string]int{"alice": 100, "bob": 50})
+	ledger.Rate("wuerzburg", map[string]int{"alice": 100, "bob": 50})
+	ledger.Rate("berlin", map[string]int{"alice": 0, "bob": 50})
+	require.NoError(t, ledger.Close())
+
+	reopened, err := Open(path)
+	require.NoError(t, err)
+	defer func() { _ = reopened.Close() }()
+	tests := []struct {
+		name        string
+		profile     string
+		streetList  string
+		wantLists   []string
+		wantRatings []int
+		wantGames   []int
+	}{
+		{
+			name:        "all street lists",
+			profile:     "alice",
+			wantLists:   []string{"berlin", "wuerzburg"},
+			wantRatings: []int{1480, 1538},
+			wantGames:   []int{1, 2},
+		},
+		{
+			name:        "one street list",
+			profile:     "bob",
+			streetList:  "wuerzburg",
+			wantLists:   []string{"wuerzburg"},
+			wantRatings: []int{1462},
+			wantGames:   []int{2},
+		},
+		{name: "unknown profile", profile: "carol"},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				ratings := reopened.Ratings(tt.profile, tt.streetList)
+				require.Len(t, ratings, len(tt.wantLists))
+				for index, rating := range ratings {
+					assert.Equal(t, tt.wantLists[index], rating.StreetList)
+					assert.Equal(t, tt.wantRatings[index], rating.Rating)
+					assert.Equal(t, tt.wantGames[index], rating.Games)
+					assert.Len(t, rating.History, rating.Games)
+				}
+			},
+		)
+	}
+}
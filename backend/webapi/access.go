@@ -0,0 +1,122 @@
+package webapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/fafeitsch/city-knowledge-contest/backend/contest"
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	maxFailedAttempts     = 5
+	failedAttemptsWindow  = time.Minute
+	defaultInviteValidity = time.Hour
+	maxInviteValidity     = 7 * 24 * time.Hour
+)
+
+var errTooManyAttempts = codedError{
+	code: tooManyAttemptsErrorCode,
+	err:  fmt.Errorf("too many failed attempts, please try again later"),
+}
+
+type attemptLimiter struct {
+	sync.Mutex
+	attempts map[string][]time.Time
+}
+
+func (a *attemptLimiter) blocked(address string) bool {
+	a.Lock()
+	defer a.Unlock()
+	return len(a.recent(address)) >= maxFailedAttempts
+}
+
+func (a *attemptLimiter) fail(address string) {
+	a.Lock()
+	defer a.Unlock()
+	a.attempts[address] = append(a.recent(address), time.Now())
+}
+
+func (a *attemptLimiter) prune() {
+	a.Lock()
+	defer a.Unlock()
+	for address := range a.attempts {
+		a.recent(address)
+	}
+}
+
+func (a *attemptLimiter) recent(address string) []time.Time {
+	threshold := time.Now().Add(-failedAttemptsWindow)
+	result := make([]time.Time, 0, len(a.attempts[address]))
+	for _, attempt := range a.attempts[address] {
+		if attempt.After(threshold) {
+			result = append(result, attempt)
+		}
+	}
+	if len(result) == 0 {
+		delete(a.attempts, address)
+	} else {
+		a.attempts[address] = result
+	}
+	return result
+}
+
+func hashPasswordAttempt(room *contest.Room, password string) []byte {
+	room.Lock()
+	salt := room.PasswordSalt()
+	room.Unlock()
+	return contest.HashPassword(salt, password)
+}
+
+func (r *roomContainer) checkAccess(
+	room *contest.Room, passwordHash []byte, request joinRequest, address string,
+) error {
+	if room.Admit(passwordHash, request.InviteToken) {
+		return nil
+	}
+	r.attempts.fail(address)
+	return codedError{
+		code: accessDeniedErrorCode,
+		err:  fmt.Errorf("wrong password or invalid invite token for room \"%s\"", request.RoomKey),
+	}
+}
+
+type createInviteRequest struct {
+	RoomKey      string `json:"roomKey"`
+	PlayerKey    string `json:"playerKey"`
+	PlayerSecret string `json:"playerSecret"`
+	ValiditySec  int    `json:"validitySec"`
+}
+
+type createInviteResponse struct {
+	InviteToken string    `json:"inviteToken"`
+	Expires     time.Time `json:"expires"`
+}
+
+func (r *roomContainer) createInvite(message json.RawMessage, address string) (*rpcRequestContext, error) {
+	request := parseMessage[createInviteRequest](message)
+	room, err := r.validatePermission(
+		request.RoomKey, request.PlayerKey, request.PlayerSecret, contest.InvitePermission,
+	)
+	if err != nil {
+		return &rpcRequestContext{release: unlockRoom(room)}, err
+	}
+	validity := time.Duration(request.ValiditySec) * time.Second
+	if validity == 0 {
+		validity = defaultInviteValidity
+	}
+	if validity < 0 || validity > maxInviteValidity {
+		return &rpcRequestContext{release: unlockRoom(room)}, fmt.Errorf(
+			"the validity of an invite must be between 1 second and %v", maxInviteValidity,
+		)
+	}
+	return &rpcRequestContext{
+		process: func() (any, error) {
+			invite := room.CreateInvite(validity)
+			log.Printf("Player \"%s\" created an invite for room \"%s\".", request.PlayerKey, room.Key())
+			return createInviteResponse{InviteToken: invite.Token, Expires: invite.Expires}, nil
+		},
+		release: unlockRoom(room),
+	}, nil
+}
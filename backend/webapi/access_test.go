@@ -0,0 +1,95 @@
+package webapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func protectTestRoom(t *testing.T, server *RpcServer, room createRoomResponse, password string, inviteOnly bool) {
+	_, rpcErr := call[updateRoomResponse](
+		t, server, "updateRoom", roomUpdateRequest{
+			RoomKey:      room.RoomKey,
+			PlayerKey:    room.PlayerKey,
+			PlayerSecret: room.PlayerSecret,
+			Password:     &password,
+			InviteOnly:   &inviteOnly,
+		},
+	)
+	require.Nil(t, rpcErr)
+}
+
+func TestRoomContainer_joinRoomAccess(t *testing.T) {
+	tests := []struct {
+		name       string
+		password   string
+		inviteOnly bool
+		attempt    string
+		invite     bool
+		wantCode   int
+	}{
+		{name: "open room"},
+		{name: "right password", password: "secret", attempt: "secret"},
+		{name: "wrong password", password: "secret", attempt: "guess", wantCode: accessDeniedErrorCode},
+		{name: "invite instead of password", password: "secret", invite: true},
+		{name: "invite only without invite", inviteOnly: true, wantCode: accessDeniedErrorCode},
+		{name: "invite only with invite", inviteOnly: true, invite: true},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				server := New(Options{})
+				room := createTestRoom(t, server, "Alice")
+				protectTestRoom(t, server, room, tt.password, tt.inviteOnly)
+				request := joinRequest{Name: "Bob", RoomKey: room.RoomKey, Password: tt.attempt}
+				if tt.invite {
+					invite, rpcErr := call[createInviteResponse](
+						t, server, "createInvite", createInviteRequest{
+							RoomKey:      room.RoomKey,
+							PlayerKey:    room.PlayerKey,
+							PlayerSecret: room.PlayerSecret,
+						},
+					)
+					require.Nil(t, rpcErr)
+					request.InviteToken = invite.InviteToken
+				}
+				joined, rpcErr := call[joinResponse](t, server, "joinRoom", request)
+				if tt.wantCode != 0 {
+					require.NotNil(t, rpcErr)
+					assert.Equal(t, tt.wantCode, rpcErr.Code)
+					return
+				}
+				require.Nil(t, rpcErr)
+				assert.Equal(t, "Bob", joined.Name)
+				spectator := request
+				spectator.Name = "Screen"
+				_, rpcErr = call[joinResponse](t, server, "spectateRoom", spectator)
+				if tt.invite {
+					require.NotNil(t, rpcErr)
+					assert.Equal(t, accessDeniedErrorCode, rpcErr.Code)
+				} else {
+					assert.Nil(t, rpcErr)
+				}
+			},
+		)
+	}
+}
+
+func TestRoomContainer_joinRoomRateLimit(t *testing.T) {
+	server := New(Options{})
+	room := createTestRoom(t, server, "Alice")
+	protectTestRoom(t, server, room, "secret", false)
+	for attempt := 0; attempt < maxFailedAttempts; attempt++ {
+		_, rpcErr := call[joinResponse](
+			t, server, "joinRoom", joinRequest{Name: "Mallory", RoomKey: room.RoomKey, Password: "guess"},
+		)
+		require.NotNil(t, rpcErr)
+		assert.Equal(t, accessDeniedErrorCode, rpcErr.Code)
+	}
+	_, rpcErr := call[joinResponse](
+		t, server, "joinRoom", joinRequest{Name: "Mallory", RoomKey: room.RoomKey, Password: "secret"},
+	)
+	require.NotNil(t, rpcErr)
+	assert.Equal(t, tooManyAttemptsErrorCode, rpcErr.Code)
+}
@@ -0,0 +1,216 @@
+package webapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/fafeitsch/city-knowledge-contest/backend/challenge"
+	"github.com/fafeitsch/city-knowledge-contest/backend/contest"
+	"github.com/fafeitsch/city-knowledge-contest/backend/geodata"
+	"log"
+	"path/filepath"
+	"time"
+)
+
+const (
+	defaultDailyRankingLimit = 50
+	dailyStandingRankingSize = 10
+)
+
+type dailyArchive struct {
+	container *roomContainer
+}
+
+func (d dailyArchive) Archive(record contest.GameRecord) {
+	d.container.recordDailyChallenge(record)
+}
+
+type startDailyRequest struct {
+	Name         string `json:"name"`
+	ListFileName string `json:"listFileName"`
+	ProfileToken string `json:"profileToken"`
+}
+
+type startDailyResponse struct {
+	Date              string `json:"date"`
+	RoomKey           string `json:"roomKey"`
+	Name              string `json:"name"`
+	PlayerKey         string `json:"playerKey"`
+	PlayerSecret      string `json:"playerSecret"`
+	ProfileId         string `json:"profileId,omitempty"`
+	ListName          string `json:"listName"`
+	NumberOfQuestions int    `json:"numberOfQuestions"`
+}
+
+func (r *roomContainer) startDailyChallenge(message json.RawMessage, address string) (*rpcRequestContext, error) {
+	request := parseMessage[startDailyRequest](message)
+	playerProfile, name, err := r.resolveProfile(request.ProfileToken, request.Name)
+	if err != nil {
+		return nil, err
+	}
+	if len(name) == 0 {
+		return nil, fmt.Errorf("a player name must not be empty")
+	}
+	streetList, err := geodata.ReadStreetList(filepath.Base(request.ListFileName))
+	if err != nil {
+		return nil, fmt.Errorf("could not load street list: %s", err)
+	}
+	date := challenge.Today()
+	if r.daily.Played(date, streetList.FileName, playerProfile.Id) {
+		return nil, fmt.Errorf("today's challenge for \"%s\" has already been played", streetList.Name)
+	}
+	return &rpcRequestContext{
+		process: func() (any, error) {
+			if !r.daily.Start(date, streetList.FileName, playerProfile.Id) {
+				return nil, fmt.Errorf("today's challenge for \"%s\" has already been played", streetList.Name)
+			}
+			room := contest.NewRoom(challenge.DailySeed(date, streetList.FileName))
+			room.SetArchive(dailyArchive{container: r})
+			room.SetChallenge(challenge.DailyChallenge(date))
+			options := room.Options()
+			options.StreetList = streetList
+			player := r.openSoloRoom(room, name, address, playerProfile.Id, options)
+			log.Printf("Player \"%s\" (\"%s\") started the daily challenge %s in room \"%s\".", player.Key, player.Name, date, room.Key())
+			return startDailyResponse{
+				Date:              date,
+				RoomKey:           room.Key(),
+				Name:              player.Name,
+				PlayerKey:         player.Key,
+				PlayerSecret:      player.Secret,
+				ProfileId:         playerProfile.Id,
+				ListName:          streetList.Name,
+				NumberOfQuestions: options.NumberOfQuestions,
+			}, nil
+		},
+	}, nil
+}
+
+type dailyRequest struct {
+	ListFileName string `json:"listFileName"`
+	Date         string `json:"date"`
+	RoomKey      string `json:"roomKey"`
+	ProfileToken string `json:"profileToken"`
+	Limit        int    `json:"limit"`
+}
+
+type dailyResponse struct {
+	Date         string                  `json:"date"`
+	ListFileName string                  `json:"listFileName"`
+	Ranking      []challenge.DailyResult `json:"ranking"`
+	Participants int                     `json:"participants"`
+	Rank         int                     `json:"rank,omitempty"`
+	Result       *challenge.DailyResult  `json:"result,omitempty"`
+	Streak       *challenge.Streak       `json:"streak,omitempty"`
+}
+
+func (r *roomContainer) getDailyChallenge(message json.RawMessage, address string) (*rpcRequestContext, error) {
+	request := parseMessage[dailyRequest](message)
+	playerProfile, _, err := r.resolveProfile(request.ProfileToken, "")
+	if err != nil {
+		return nil, err
+	}
+	if request.ListFileName == "" {
+		return nil, fmt.Errorf("a street list must be given")
+	}
+	streetList := filepath.Base(request.ListFileName)
+	date := request.Date
+	if date == "" {
+		date = challenge.Today()
+	}
+	if _, err := time.Parse(challenge.DateFormat, date); err != nil {
+		return nil, fmt.Errorf("the date \"%s\" is not in the format YYYY-MM-DD", date)
+	}
+	limit := request.Limit
+	if limit <= 0 {
+		limit = defaultDailyRankingLimit
+	}
+	return &rpcRequestContext{
+		process: func() (any, error) {
+			ranking := r.daily.Ranking(date, streetList)
+			response := dailyResponse{Date: date, ListFileName: streetList, Participants: len(ranking)}
+			own, ok := challenge.DailyResult{}, false
+			for _, result := range ranking {
+				if playerProfile.Id != "" && result.ProfileId == playerProfile.Id {
+					own, ok = result, true
+					break
+				}
+			}
+			if !ok && request.RoomKey != "" {
+				own, ok = r.daily.Result(request.RoomKey)
+				ok = ok && own.Date == date && own.StreetList == streetList
+			}
+			if ok {
+				response.Rank = challenge.Rank(ranking, own)
+				response.Result = &own
+			}
+			if len(ranking) > limit {
+				ranking = ranking[:limit]
+			}
+			response.Ranking = ranking
+			profileId := playerProfile.Id
+			if profileId == "" && response.Result != nil {
+				profileId = response.Result.ProfileId
+			}
+			if profileId != "" {
+				streak := r.daily.Streak(profileId, challenge.Today())
+				response.Streak = &streak
+			}
+			return response, nil
+		},
+	}, nil
+}
+
+func (r *roomContainer) recordDailyChallenge(record contest.GameRecord) {
+	r.daily.Archive(record)
+	result, ok := r.daily.Result(record.RoomKey)
+	if !ok {
+		return
+	}
+	ranking := r.daily.Ranking(result.Date, result.StreetList)
+	standing := contest.DailyStanding{
+		Date:         result.Date,
+		ListFileName: result.StreetList,
+		Points:       result.Points,
+		Ranked:       result.ProfileId != "",
+		Rank:         challenge.Rank(ranking, result),
+		Participants: len(ranking),
+		Ranking:      make([]contest.DailyRankingEntry, 0, dailyStandingRankingSize),
+	}
+	for index, entry := range ranking {
+		if index == dailyStandingRankingSize {
+			break
+		}
+		standing.Ranking = append(
+			standing.Ranking, contest.DailyRankingEntry{Name: entry.Name, Points: entry.Points, Correct: entry.Correct},
+		)
+	}
+	if result.ProfileId != "" {
+		streak := r.daily.Streak(result.ProfileId, result.Date)
+		standing.Streak = streak.Current
+		standing.BestStreak = streak.Best
+	}
+	r.RLock()
+	room, ok := r.openRooms[record.RoomKey]
+	r.RUnlock()
+	if !ok {
+		return
+	}
+	room.Lock()
+	room.NotifyDailyStanding(standing)
+	room.Unlock()
+}
+
+func (r *roomContainer) openSoloRoom(
+	room *contest.Room, name string, address string, profile string, options contest.RoomOptions,
+) contest.Player {
+	room.Lock()
+	player := room.Join(name, address)
+	room.SetProfile(player.Key, profile)
+	options.MaxPlayers = 1
+	options.RefuseLateJoin = true
+	room.SetOptions(options, player.Key)
+	room.Unlock()
+	r.Lock()
+	r.openRooms[room.Key()] = room
+	r.Unlock()
+	return player
+}
@@ -0,0 +1,164 @@
+package webapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fafeitsch/city-knowledge-contest/backend/challenge"
+	"github.com/fafeitsch/city-knowledge-contest/backend/contest"
+	"github.com/fafeitsch/city-knowledge-contest/backend/geodata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoomContainer_recordDailyChallenge(t *testing.T) {
+	date := challenge.Today()
+	streetList := &geodata.StreetList{FileName: "test.json", Name: "Test"}
+	tests := []struct {
+		name    string
+		profile string
+		want    contest.DailyStanding
+	}{
+		{
+			name: "anonymous run",
+			want: contest.DailyStanding{
+				Points:       80,
+				Rank:         1,
+				Participants: 1,
+				Ranking:      []contest.DailyRankingEntry{{Name: "Bob", Points: 70, Correct: 7}},
+			},
+		},
+		{
+			name:    "profile",
+			profile: "alice",
+			want: contest.DailyStanding{
+				Points:       80,
+				Ranked:       true,
+				Rank:         1,
+				Participants: 2,
+				Ranking: []contest.DailyRankingEntry{
+					{Name: "Alice", Points: 80, Correct: 8},
+					{Name: "Bob", Points: 70, Correct: 7},
+				},
+				Streak:     1,
+				BestStreak: 1,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				server := New(Options{})
+				container := server.roomContainer
+				container.daily.Archive(
+					contest.GameRecord{
+						RoomKey:   "bob",
+						Challenge: challenge.DailyChallenge(date),
+						Options:   contest.RoomOptions{StreetList: streetList},
+						Summary: contest.GameSummary{
+							Game:    1,
+							Players: []contest.PlayerSummary{{Name: "Bob", ProfileId: "bob", Points: 70, Correct: 7}},
+						},
+					},
+				)
+				room := contest.NewRoom(challenge.DailySeed(date, streetList.FileName))
+				room.SetChallenge(challenge.DailyChallenge(date))
+				options := room.Options()
+				options.StreetList = streetList
+				player := container.openSoloRoom(room, "Alice", "192.0.2.1", tt.profile, options)
+				messages := make(chan websocketMessage, 16)
+				room.Lock()
+				room.Connect(
+					player.Key, &websocketNotifier{
+						write: func(msg any) {
+							if message, ok := msg.(websocketMessage); ok {
+								messages <- message
+							}
+						},
+					}, "192.0.2.1",
+				)
+				room.Unlock()
+				container.recordDailyChallenge(
+					contest.GameRecord{
+						RoomKey:   room.Key(),
+						Challenge: challenge.DailyChallenge(date),
+						Options:   options,
+						Summary: contest.GameSummary{
+							Game: 1,
+							Players: []contest.PlayerSummary{
+								{PlayerKey: player.Key, Name: "Alice", ProfileId: tt.profile, Points: 80, Correct: 8},
+							},
+						},
+						Finished: time.Now(),
+					},
+				)
+				want := tt.want
+				want.Date = date
+				want.ListFileName = streetList.FileName
+				for {
+					select {
+					case message := <-messages:
+						if message.Topic != "dailyStanding" {
+							continue
+						}
+						assert.Equal(t, want, message.Payload)
+						return
+					case <-time.After(time.Second):
+						require.Fail(t, "no daily standing received")
+					}
+				}
+			},
+		)
+	}
+}
+
+func TestRoomContainer_getDailyChallenge(t *testing.T) {
+	server := New(Options{})
+	date := challenge.Today()
+	for _, player := range []contest.PlayerSummary{
+		{Name: "Alice", ProfileId: "alice", Points: 90},
+		{Name: "Guest", Points: 80},
+		{Name: "Bob", ProfileId: "bob", Points: 70},
+	} {
+		server.roomContainer.daily.Archive(
+			contest.GameRecord{
+				RoomKey:   player.Name,
+				Challenge: challenge.DailyChallenge(date),
+				Options:   contest.RoomOptions{StreetList: &geodata.StreetList{FileName: "test.json"}},
+				Summary:   contest.GameSummary{Game: 1, Players: []contest.PlayerSummary{player}},
+				Finished:  time.Now(),
+			},
+		)
+	}
+	tests := []struct {
+		name     string
+		roomKey  string
+		wantRank int
+		wantName string
+	}{
+		{name: "without result"},
+		{name: "ranked run", roomKey: "Bob", wantRank: 2, wantName: "Bob"},
+		{name: "anonymous run", roomKey: "Guest", wantRank: 2, wantName: "Guest"},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				response, rpcErr := call[dailyResponse](
+					t, server, "getDailyChallenge", dailyRequest{ListFileName: "test.json", RoomKey: tt.roomKey},
+				)
+				require.Nil(t, rpcErr)
+				assert.Equal(t, 2, response.Participants)
+				require.Len(t, response.Ranking, 2)
+				assert.Equal(t, "Alice", response.Ranking[0].Name)
+				assert.Equal(t, "Bob", response.Ranking[1].Name)
+				assert.Equal(t, tt.wantRank, response.Rank)
+				if tt.wantName == "" {
+					assert.Nil(t, response.Result)
+					return
+				}
+				require.NotNil(t, response.Result)
+				assert.Equal(t, tt.wantName, response.Result.Name)
+			},
+		)
+	}
+}
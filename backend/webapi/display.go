@@ -0,0 +1,110 @@
+package webapi
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"github.com/fafeitsch/city-knowledge-contest/backend/contest"
+	"log"
+	"net/http"
+	"nhooyr.io/websocket"
+	"nhooyr.io/websocket/wsjson"
+	"strings"
+	"time"
+)
+
+func (r *roomContainer) upgradeToDisplay(writer http.ResponseWriter, request *http.Request, options Options) error {
+	parts := strings.Split(request.URL.Path, "/")
+	if len(parts) != 4 {
+		writer.WriteHeader(http.StatusNotFound)
+		return nil
+	}
+	r.RLock()
+	room, roomExists := r.openRooms[parts[2]]
+	r.RUnlock()
+	if !roomExists {
+		writer.WriteHeader(http.StatusNotFound)
+		return nil
+	}
+	room.Lock()
+	token := room.DisplayToken()
+	room.Unlock()
+	if subtle.ConstantTimeCompare([]byte(token), []byte(parts[3])) != 1 {
+		writer.WriteHeader(http.StatusUnauthorized)
+		return nil
+	}
+	connection, err := websocket.Accept(
+		writer, request, &websocket.AcceptOptions{InsecureSkipVerify: options.AllowCors},
+	)
+	if err != nil {
+		return fmt.Errorf("could not upgrade to websockets: %v", err)
+	}
+	notifier := &displayNotifier{
+		websocketNotifier{
+			write: func(msg any) {
+				_ = wsjson.Write(request.Context(), connection, msg)
+			},
+		},
+	}
+	room.Lock()
+	state := room.AddDisplay(notifier)
+	message := initialDisplayMessage{
+		Players:    convertPlayers(room.Players()),
+		Options:    convertRoomOptions(room.Options(), ""),
+		Started:    room.Started(),
+		Scoreboard: state.Scoreboard,
+	}
+	if state.Question != "" {
+		message.Question = &displayQuestion{
+			Find:           state.Question,
+			QuestionNumber: state.QuestionNumber,
+			Deadline:       state.Deadline.UnixMilli(),
+		}
+	}
+	_ = wsjson.Write(request.Context(), connection, websocketMessage{Topic: "displayConnected", Payload: message})
+	room.Unlock()
+	log.Printf("Established display connection to room \"%s\".", room.Key())
+	closeContext := connection.CloseRead(request.Context())
+	var pingErr error
+	for pingErr == nil {
+		pingErr = connection.Ping(closeContext)
+		time.Sleep(10 * time.Second)
+	}
+	log.Printf("Display connection to room \"%s\" lost: %v", room.Key(), pingErr)
+	room.Lock()
+	room.RemoveDisplay(notifier)
+	room.Unlock()
+	_ = connection.Close(websocket.StatusNormalClosure, "")
+	return nil
+}
+
+type initialDisplayMessage struct {
+	Players    []playerInfo              `json:"players"`
+	Options    roomUpdateMessage         `json:"options"`
+	Started    bool                      `json:"started"`
+	Scoreboard []contest.ScoreboardEntry `json:"scoreboard"`
+	Question   *displayQuestion          `json:"question,omitempty"`
+}
+
+type displayQuestion struct {
+	Find           string `json:"find"`
+	QuestionNumber int    `json:"questionNumber"`
+	Deadline       int64  `json:"deadline"`
+}
+
+type displayNotifier struct {
+	websocketNotifier
+}
+
+func (d *displayNotifier) NotifyQuestionDeadline(questionNumber int, deadline time.Time) {
+	message := map[string]any{"questionNumber": questionNumber, "deadline": deadline.UnixMilli()}
+	d.write(websocketMessage{Topic: "questionDeadline", Payload: message})
+}
+
+func (d *displayNotifier) NotifyAnswerCount(answered int, total int) {
+	message := map[string]any{"answered": answered, "total": total}
+	d.write(websocketMessage{Topic: "answerCount", Payload: message})
+}
+
+func (d *displayNotifier) NotifyScoreboard(scoreboard []contest.ScoreboardEntry) {
+	d.write(websocketMessage{Topic: "scoreboard", Payload: scoreboard})
+}
@@ -0,0 +1,58 @@
+package webapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"nhooyr.io/websocket"
+	"nhooyr.io/websocket/wsjson"
+)
+
+func TestRpcServer_upgradeToDisplay(t *testing.T) {
+	server := New(Options{})
+	room := createTestRoom(t, server, "Alice")
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+	address := "ws" + strings.TrimPrefix(httpServer.URL, "http")
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+	}{
+		{name: "valid token", path: "/display/" + room.RoomKey + "/" + room.DisplayToken},
+		{name: "valid token with query", path: "/display/" + room.RoomKey + "/" + room.DisplayToken + "?theme=dark"},
+		{name: "wrong token", path: "/display/" + room.RoomKey + "/" + room.PlayerKey, wantStatus: http.StatusUnauthorized},
+		{name: "missing token", path: "/display/" + room.RoomKey, wantStatus: http.StatusNotFound},
+		{name: "unknown room", path: "/display/unknown/" + room.DisplayToken, wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				connection, response, err := websocket.Dial(ctx, address+tt.path, nil)
+				if tt.wantStatus != 0 {
+					require.Error(t, err)
+					assert.Equal(t, tt.wantStatus, response.StatusCode)
+					return
+				}
+				require.NoError(t, err)
+				defer func() { _ = connection.Close(websocket.StatusNormalClosure, "") }()
+				var message struct {
+					Topic   string                `json:"topic"`
+					Payload initialDisplayMessage `json:"payload"`
+				}
+				require.NoError(t, wsjson.Read(ctx, connection, &message))
+				assert.Equal(t, "displayConnected", message.Topic)
+				require.Len(t, message.Payload.Players, 1)
+				assert.Equal(t, "Alice", message.Payload.Players[0].Name)
+			},
+		)
+	}
+}
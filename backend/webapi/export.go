@@ -0,0 +1,116 @@
+package webapi
+
+import (
+	"crypto/subtle"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/fafeitsch/city-knowledge-contest/backend/contest"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	exportTokenHeader  = "ckc-export-token"
+	playerSecretHeader = "ckc-player-secret"
+)
+
+func (r *roomContainer) serveExport(writer http.ResponseWriter, request *http.Request) {
+	parts := strings.Split(request.URL.Path, "/")
+	if len(parts) != 4 {
+		writer.WriteHeader(http.StatusNotFound)
+		return
+	}
+	gameId, format, ok := strings.Cut(parts[3], ".")
+	game, err := strconv.Atoi(gameId)
+	if !ok || err != nil || (format != "csv" && format != "json") {
+		writer.WriteHeader(http.StatusNotFound)
+		return
+	}
+	r.RLock()
+	room, ok := r.openRooms[parts[2]]
+	r.RUnlock()
+	if !ok {
+		writer.WriteHeader(http.StatusNotFound)
+		return
+	}
+	token := request.Header.Get(exportTokenHeader)
+	secret := request.Header.Get(playerSecretHeader)
+	room.Lock()
+	authorized := token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(room.ExportToken())) == 1
+	player, ok := room.FindPlayer(request.URL.Query().Get("playerKey"))
+	if ok && secret != "" && subtle.ConstantTimeCompare([]byte(secret), []byte(player.Secret)) == 1 {
+		authorized = true
+	}
+	summary, found := room.GameSummary(game)
+	room.Unlock()
+	if !authorized {
+		writer.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if !found {
+		writer.WriteHeader(http.StatusNotFound)
+		return
+	}
+	fileName := fmt.Sprintf("%s-%d.%s", room.Key(), game, format)
+	writer.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", fileName))
+	if format == "json" {
+		writer.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(writer).Encode(summary)
+		return
+	}
+	writer.Header().Set("Content-Type", "text/csv")
+	writeCsvExport(writer, summary)
+}
+
+func writeCsvExport(writer http.ResponseWriter, summary contest.GameSummary) {
+	names := make(map[string]string, len(summary.Players))
+	totals := make(map[string]int, len(summary.Players))
+	for _, player := range summary.Players {
+		names[player.PlayerKey] = player.Name
+		totals[player.PlayerKey] = player.Points
+	}
+	output := csv.NewWriter(writer)
+	_ = output.Write(
+		[]string{
+			"game", "question", "street", "solutionLat", "solutionLng", "playerKey", "playerName", "answered",
+			"guessLat", "guessLng", "distanceMeters", "correct", "responseTimeMs", "points", "attempts", "totalPoints",
+		},
+	)
+	for _, question := range summary.Questions {
+		for _, answer := range question.Answers {
+			guessLat, guessLng, distance := "", "", ""
+			if answer.Guess != nil {
+				guessLat = formatFloat(answer.Guess.Lat)
+				guessLng = formatFloat(answer.Guess.Lng)
+				distance = strconv.FormatFloat(answer.DistanceMeters, 'f', 0, 64)
+			}
+			_ = output.Write(
+				[]string{
+					strconv.Itoa(summary.Game),
+					strconv.Itoa(question.Number),
+					question.Street,
+					formatFloat(question.Solution.Lat),
+					formatFloat(question.Solution.Lng),
+					answer.PlayerKey,
+					names[answer.PlayerKey],
+					strconv.FormatBool(answer.Answered),
+					guessLat,
+					guessLng,
+					distance,
+					strconv.FormatBool(answer.Correct),
+					strconv.FormatInt(answer.ResponseTimeMs, 10),
+					strconv.Itoa(answer.Points),
+					strconv.Itoa(answer.Attempts),
+					strconv.Itoa(totals[answer.PlayerKey]),
+				},
+			)
+		}
+	}
+	output.Flush()
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', 6, 64)
+}
@@ -0,0 +1,122 @@
+package webapi
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fafeitsch/city-knowledge-contest/backend/contest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoomContainer_serveExport(t *testing.T) {
+	server := New(Options{})
+	created := createTestRoom(t, server, "Alice")
+	playTestGame(t, server, created)
+	export := func(path string, header string, value string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodGet, "/export/"+path, nil)
+		if header != "" {
+			request.Header.Set(header, value)
+		}
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, request)
+		return recorder
+	}
+	playerPath := created.RoomKey + "/1.json?playerKey=" + created.PlayerKey
+	tests := []struct {
+		name   string
+		path   string
+		header string
+		value  string
+		status int
+	}{
+		{
+			name:   "export token",
+			path:   created.RoomKey + "/1.json",
+			header: exportTokenHeader,
+			value:  created.ExportToken,
+			status: http.StatusOK,
+		},
+		{
+			name:   "player secret",
+			path:   playerPath,
+			header: playerSecretHeader,
+			value:  created.PlayerSecret,
+			status: http.StatusOK,
+		},
+		{
+			name:   "wrong export token",
+			path:   created.RoomKey + "/1.json",
+			header: exportTokenHeader,
+			value:  "wrong",
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:   "wrong player secret",
+			path:   playerPath,
+			header: playerSecretHeader,
+			value:  "wrong",
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:   "export token in query",
+			path:   created.RoomKey + "/1.json?token=" + created.ExportToken,
+			status: http.StatusUnauthorized,
+		},
+		{name: "secret in query", path: playerPath + "&secret=" + created.PlayerSecret, status: http.StatusUnauthorized},
+		{
+			name:   "unknown game",
+			path:   created.RoomKey + "/2.json",
+			header: exportTokenHeader,
+			value:  created.ExportToken,
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "unknown format",
+			path:   created.RoomKey + "/1.xml",
+			header: exportTokenHeader,
+			value:  created.ExportToken,
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "unknown room",
+			path:   "unknown/1.json",
+			header: exportTokenHeader,
+			value:  created.ExportToken,
+			status: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				assert.Equal(t, tt.status, export(tt.path, tt.header, tt.value).Code)
+			},
+		)
+	}
+
+	recorder := export(created.RoomKey+"/1.csv", exportTokenHeader, created.ExportToken)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "text/csv", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, "attachment; filename=\""+created.RoomKey+"-1.csv\"", recorder.Header().Get("Content-Disposition"))
+	records, err := csv.NewReader(recorder.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "game", records[0][0])
+	assert.Equal(
+		t, []string{"1", "0", testStreet, "49.790000", "9.930000", created.PlayerKey, "Alice", "true"}, records[1][:8],
+	)
+
+	recorder = export(created.RoomKey+"/1.json", exportTokenHeader, created.ExportToken)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	var summary contest.GameSummary
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&summary))
+	assert.Equal(t, 1, summary.Game)
+	require.Len(t, summary.Questions, 1)
+	assert.Equal(t, testStreet, summary.Questions[0].Street)
+	require.Len(t, summary.Players, 1)
+	assert.Equal(t, "Alice", summary.Players[0].Name)
+}
@@ -0,0 +1,270 @@
+package webapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/fafeitsch/city-knowledge-contest/backend/challenge"
+	"github.com/fafeitsch/city-knowledge-contest/backend/contest"
+	"github.com/fafeitsch/city-knowledge-contest/backend/geodata"
+	"log"
+	"time"
+)
+
+type friendArchive struct {
+	container *roomContainer
+}
+
+func (f friendArchive) Archive(record contest.GameRecord) {
+	f.container.recordFriendChallenge(record)
+}
+
+type createChallengeRequest struct {
+	PlayerKey    string `json:"playerKey"`
+	PlayerSecret string `json:"playerSecret"`
+	RoomKey      string `json:"roomKey"`
+	Game         int    `json:"game"`
+}
+
+type createChallengeResponse struct {
+	ChallengeId string `json:"challengeId"`
+	Path        string `json:"path"`
+	Points      int    `json:"points"`
+}
+
+func (r *roomContainer) createChallenge(message json.RawMessage, address string) (*rpcRequestContext, error) {
+	request := parseMessage[createChallengeRequest](message)
+	room, err := r.validateRoomAndPlayer(request.RoomKey, request.PlayerKey, request.PlayerSecret)
+	if err != nil {
+		return &rpcRequestContext{release: unlockRoom(room)}, err
+	}
+	summary, ok := room.Summary()
+	if request.Game > 0 {
+		summary, ok = room.GameSummary(request.Game)
+	}
+	if !ok {
+		return &rpcRequestContext{release: unlockRoom(room)}, fmt.Errorf(
+			"no finished game found in room \"%s\"", request.RoomKey,
+		)
+	}
+	options := summary.Options
+	if options.Mode != contest.ClassicMode || options.StreetList == nil || len(summary.Questions) == 0 {
+		return &rpcRequestContext{release: unlockRoom(room)}, fmt.Errorf(
+			"only finished games in classic mode can be turned into a challenge",
+		)
+	}
+	var creator *contest.PlayerSummary
+	for index, player := range summary.Players {
+		if player.PlayerKey == request.PlayerKey {
+			creator = &summary.Players[index]
+		}
+	}
+	if creator == nil {
+		return &rpcRequestContext{release: unlockRoom(room)}, fmt.Errorf(
+			"you did not take part in game %d of room \"%s\"", summary.Game, request.RoomKey,
+		)
+	}
+	streets := make([]geodata.Street, 0, len(summary.Questions))
+	for _, question := range summary.Questions {
+		solution := question.Solution
+		streets = append(streets, geodata.Street{Name: question.Street, Coordinate: &solution})
+	}
+	friend := challenge.Friend{
+		CreatorName:    creator.Name,
+		CreatorProfile: creator.ProfileId,
+		CreatorRoom:    room.Key(),
+		CreatorPlayer:  creator.PlayerKey,
+		StreetList:     options.StreetList.FileName,
+		Streets:        streets,
+		MaxAnswerTime:  options.MaxAnswerTime,
+		LockIn:         options.LockIn,
+		Results: []challenge.FriendResult{
+			{
+				RoomKey:   room.Key(),
+				ProfileId: creator.ProfileId,
+				Name:      creator.Name,
+				Points:    creator.Points,
+				Correct:   creator.Correct,
+				Questions: creator.Questions,
+				Creator:   true,
+				Finished:  time.Now(),
+			},
+		},
+	}
+	return &rpcRequestContext{
+		process: func() (any, error) {
+			created, err := r.friends.Create(friend)
+			if err != nil {
+				log.Printf("could not persist challenge \"%s\": %v", created.Id, err)
+			}
+			log.Printf("Player \"%s\" created challenge \"%s\" from room \"%s\".", request.PlayerKey, created.Id, room.Key())
+			return createChallengeResponse{
+				ChallengeId: created.Id,
+				Path:        "/challenge/" + created.Id,
+				Points:      creator.Points,
+			}, nil
+		},
+		release: unlockRoom(room),
+	}, nil
+}
+
+type challengeRequest struct {
+	ChallengeId  string `json:"challengeId"`
+	Name         string `json:"name"`
+	ProfileToken string `json:"profileToken"`
+	RoomKey      string `json:"roomKey"`
+}
+
+type challengeResponse struct {
+	ChallengeId       string           `json:"challengeId"`
+	CreatorName       string           `json:"creatorName"`
+	Created           time.Time        `json:"created"`
+	ListFileName      string           `json:"listFileName"`
+	NumberOfQuestions int              `json:"numberOfQuestions"`
+	MaxAnswerTimeSec  int              `json:"maxAnswerTimeSec"`
+	Scoreboard        []challengeScore `json:"scoreboard"`
+	Rank              int              `json:"rank,omitempty"`
+}
+
+type challengeScore struct {
+	Name      string    `json:"name"`
+	ProfileId string    `json:"profileId,omitempty"`
+	Points    int       `json:"points"`
+	Correct   int       `json:"correct"`
+	Questions int       `json:"questions"`
+	Creator   bool      `json:"creator"`
+	Finished  time.Time `json:"finished"`
+}
+
+func (r *roomContainer) getChallenge(message json.RawMessage, address string) (*rpcRequestContext, error) {
+	request := parseMessage[challengeRequest](message)
+	if _, ok := r.friends.Get(request.ChallengeId); !ok {
+		return nil, fmt.Errorf("challenge \"%s\" not found", request.ChallengeId)
+	}
+	return &rpcRequestContext{
+		process: func() (any, error) {
+			friend, _ := r.friends.Get(request.ChallengeId)
+			response := challengeResponse{
+				ChallengeId:       friend.Id,
+				CreatorName:       friend.CreatorName,
+				Created:           friend.Created,
+				ListFileName:      friend.StreetList,
+				NumberOfQuestions: len(friend.Streets),
+				MaxAnswerTimeSec:  int(friend.MaxAnswerTime.Seconds()),
+				Scoreboard:        make([]challengeScore, 0, len(friend.Results)),
+			}
+			for _, result := range friend.Results {
+				response.Scoreboard = append(
+					response.Scoreboard, challengeScore{
+						Name:      result.Name,
+						ProfileId: result.ProfileId,
+						Points:    result.Points,
+						Correct:   result.Correct,
+						Questions: result.Questions,
+						Creator:   result.Creator,
+						Finished:  result.Finished,
+					},
+				)
+			}
+			if request.RoomKey != "" {
+				response.Rank = friend.Rank(request.RoomKey)
+			}
+			return response, nil
+		},
+	}, nil
+}
+
+type playChallengeResponse struct {
+	RoomKey           string `json:"roomKey"`
+	Name              string `json:"name"`
+	PlayerKey         string `json:"playerKey"`
+	PlayerSecret      string `json:"playerSecret"`
+	ProfileId         string `json:"profileId,omitempty"`
+	NumberOfQuestions int    `json:"numberOfQuestions"`
+	CreatorPoints     int    `json:"creatorPoints"`
+}
+
+func (r *roomContainer) playChallenge(message json.RawMessage, address string) (*rpcRequestContext, error) {
+	request := parseMessage[challengeRequest](message)
+	playerProfile, name, err := r.resolveProfile(request.ProfileToken, request.Name)
+	if err != nil {
+		return nil, err
+	}
+	if len(name) == 0 {
+		return nil, fmt.Errorf("a player name must not be empty")
+	}
+	friend, ok := r.friends.Get(request.ChallengeId)
+	if !ok {
+		return nil, fmt.Errorf("challenge \"%s\" not found", request.ChallengeId)
+	}
+	if r.friends.Played(friend.Id, playerProfile.Id) {
+		return nil, fmt.Errorf("you have already played challenge \"%s\"", friend.Id)
+	}
+	streetList, err := geodata.ReadStreetList(friend.StreetList)
+	if err != nil {
+		return nil, fmt.Errorf("could not load street list: %s", err)
+	}
+	return &rpcRequestContext{
+		process: func() (any, error) {
+			room := contest.NewRoom(r.seed)
+			room.SetArchive(friendArchive{container: r})
+			room.SetChallenge(challenge.FriendChallenge(friend.Id))
+			room.SetStreets(friend.Streets)
+			options := room.Options()
+			options.StreetList = streetList
+			options.NumberOfQuestions = len(friend.Streets)
+			options.MaxAnswerTime = friend.MaxAnswerTime
+			options.LockIn = friend.LockIn
+			player := r.openSoloRoom(room, name, address, playerProfile.Id, options)
+			log.Printf("Player \"%s\" (\"%s\") plays challenge \"%s\" in room \"%s\".", player.Key, player.Name, friend.Id, room.Key())
+			return playChallengeResponse{
+				RoomKey:           room.Key(),
+				Name:              player.Name,
+				PlayerKey:         player.Key,
+				PlayerSecret:      player.Secret,
+				ProfileId:         playerProfile.Id,
+				NumberOfQuestions: options.NumberOfQuestions,
+				CreatorPoints:     friend.CreatorPoints(),
+			}, nil
+		},
+	}, nil
+}
+
+func (r *roomContainer) recordFriendChallenge(record contest.GameRecord) {
+	friend, beaten, err := r.friends.Record(record)
+	if err != nil {
+		log.Printf("could not persist result of challenge \"%s\": %v", friend.Id, err)
+	}
+	for _, result := range beaten {
+		r.notifyChallengeCreator(
+			friend, contest.ChallengeResult{
+				Challenge:     friend.Id,
+				Name:          result.Name,
+				Points:        result.Points,
+				CreatorPoints: friend.CreatorPoints(),
+				Rank:          friend.Rank(result.RoomKey),
+				Participants:  len(friend.Results),
+			},
+		)
+	}
+}
+
+func (r *roomContainer) notifyChallengeCreator(friend challenge.Friend, result contest.ChallengeResult) {
+	r.RLock()
+	rooms := make([]*contest.Room, 0, len(r.openRooms))
+	for _, room := range r.openRooms {
+		rooms = append(rooms, room)
+	}
+	r.RUnlock()
+	for _, room := range rooms {
+		playerKey := ""
+		if room.Key() == friend.CreatorRoom {
+			playerKey = friend.CreatorPlayer
+		}
+		if playerKey == "" && friend.CreatorProfile == "" {
+			continue
+		}
+		room.Lock()
+		room.NotifyChallengeBeaten(playerKey, friend.CreatorProfile, result)
+		room.Unlock()
+	}
+}
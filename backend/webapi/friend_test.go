@@ -0,0 +1,40 @@
+package webapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fafeitsch/city-knowledge-contest/backend/contest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoomContainer_createChallenge(t *testing.T) {
+	server := New(Options{})
+	created := createTestRoom(t, server, "Alice")
+	room := playTestGame(t, server, created)
+	room.Lock()
+	options := room.Options()
+	options.Mode = contest.BlitzMode
+	options.GameDuration = time.Minute
+	options.MaxAnswerTime = 30 * time.Second
+	options.StreetList = nil
+	room.SetOptions(options, created.PlayerKey)
+	room.Unlock()
+	friend, rpcErr := call[createChallengeResponse](
+		t, server, "createChallenge", createChallengeRequest{
+			RoomKey:      created.RoomKey,
+			PlayerKey:    created.PlayerKey,
+			PlayerSecret: created.PlayerSecret,
+		},
+	)
+	require.Nil(t, rpcErr)
+	assert.Equal(t, "/challenge/"+friend.ChallengeId, friend.Path)
+	details, rpcErr := call[challengeResponse](t, server, "getChallenge", challengeRequest{ChallengeId: friend.ChallengeId})
+	require.Nil(t, rpcErr)
+	assert.Equal(t, "test.json", details.ListFileName)
+	assert.Equal(t, 1, details.NumberOfQuestions)
+	assert.Equal(t, 10, details.MaxAnswerTimeSec)
+	require.Len(t, details.Scoreboard, 1)
+	assert.Equal(t, "Alice", details.Scoreboard[0].Name)
+}
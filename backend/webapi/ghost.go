@@ -0,0 +1,118 @@
+package webapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/fafeitsch/city-knowledge-contest/backend/contest"
+	"github.com/fafeitsch/city-knowledge-contest/backend/geodata"
+	"log"
+)
+
+const ghostChallengePrefix = "ghost:"
+
+type ghostGameRequest struct {
+	PlayerKey    string   `json:"playerKey"`
+	PlayerSecret string   `json:"playerSecret"`
+	RoomKey      string   `json:"roomKey"`
+	Game         int      `json:"game"`
+	Ghosts       []string `json:"ghosts"`
+}
+
+type ghostGameResponse struct {
+	RoomKey           string          `json:"roomKey"`
+	Name              string          `json:"name"`
+	PlayerKey         string          `json:"playerKey"`
+	PlayerSecret      string          `json:"playerSecret"`
+	NumberOfQuestions int             `json:"numberOfQuestions"`
+	Ghosts            []contest.Ghost `json:"ghosts"`
+}
+
+func (r *roomContainer) startGhostGame(message json.RawMessage, address string) (*rpcRequestContext, error) {
+	request := parseMessage[ghostGameRequest](message)
+	room, err := r.validateRoomAndPlayer(request.RoomKey, request.PlayerKey, request.PlayerSecret)
+	if err != nil {
+		return &rpcRequestContext{release: unlockRoom(room)}, err
+	}
+	summary, ok := room.Summary()
+	if request.Game > 0 {
+		summary, ok = room.GameSummary(request.Game)
+	}
+	if !ok {
+		return &rpcRequestContext{release: unlockRoom(room)}, fmt.Errorf(
+			"no finished game found in room \"%s\"", request.RoomKey,
+		)
+	}
+	options := summary.Options
+	if options.Mode != contest.ClassicMode || options.StreetList == nil || len(summary.Questions) == 0 {
+		return &rpcRequestContext{release: unlockRoom(room)}, fmt.Errorf(
+			"only finished games in classic mode can be replayed with ghosts",
+		)
+	}
+	selected := make(map[string]bool, len(request.Ghosts))
+	for _, key := range request.Ghosts {
+		selected[key] = true
+	}
+	ghosts := make([]contest.PlayerSummary, 0, len(summary.Players))
+	for _, player := range summary.Players {
+		if len(selected) == 0 || selected[player.PlayerKey] {
+			ghosts = append(ghosts, player)
+		}
+	}
+	if len(ghosts) == 0 {
+		return &rpcRequestContext{release: unlockRoom(room)}, fmt.Errorf(
+			"none of the requested players took part in game %d", summary.Game,
+		)
+	}
+	streets := make([]geodata.Street, 0, len(summary.Questions))
+	for _, question := range summary.Questions {
+		solution := question.Solution
+		streets = append(streets, geodata.Street{Name: question.Street, Coordinate: &solution})
+	}
+	var player contest.Player
+	for _, candidate := range room.Players() {
+		if candidate.Key == request.PlayerKey {
+			player = candidate
+		}
+	}
+	return &rpcRequestContext{
+		process: func() (any, error) {
+			ghostRoom := contest.NewRoom(r.seed)
+			ghostRoom.SetChallenge(ghostChallengePrefix + room.Key())
+			ghostRoom.SetStreets(streets)
+			ghostOptions := ghostRoom.Options()
+			ghostOptions.StreetList = options.StreetList
+			ghostOptions.NumberOfQuestions = len(streets)
+			ghostOptions.MaxAnswerTime = options.MaxAnswerTime
+			ghostOptions.LockIn = options.LockIn
+			for _, ghost := range ghosts {
+				ghostRoom.AddGhost(ghost.Name, ghostAnswers(summary, ghost.PlayerKey))
+			}
+			solo := r.openSoloRoom(ghostRoom, player.Name, address, player.Profile, ghostOptions)
+			log.Printf(
+				"Player \"%s\" started a ghost game in room \"%s\" based on room \"%s\".",
+				solo.Key, ghostRoom.Key(), room.Key(),
+			)
+			return ghostGameResponse{
+				RoomKey:           ghostRoom.Key(),
+				Name:              solo.Name,
+				PlayerKey:         solo.Key,
+				PlayerSecret:      solo.Secret,
+				NumberOfQuestions: len(streets),
+				Ghosts:            ghostRoom.Ghosts(),
+			}, nil
+		},
+		release: unlockRoom(room),
+	}, nil
+}
+
+func ghostAnswers(summary contest.GameSummary, playerKey string) []contest.AnswerRecord {
+	result := make([]contest.AnswerRecord, len(summary.Questions))
+	for index, question := range summary.Questions {
+		for _, answer := range question.Answers {
+			if answer.PlayerKey == playerKey {
+				result[index] = answer
+			}
+		}
+	}
+	return result
+}
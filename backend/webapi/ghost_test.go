@@ -0,0 +1,40 @@
+package webapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fafeitsch/city-knowledge-contest/backend/contest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoomContainer_startGhostGame(t *testing.T) {
+	server := New(Options{})
+	created := createTestRoom(t, server, "Alice")
+	room := playTestGame(t, server, created)
+	room.Lock()
+	options := room.Options()
+	options.Mode = contest.BlitzMode
+	options.GameDuration = time.Minute
+	options.MaxAnswerTime = 30 * time.Second
+	room.SetOptions(options, created.PlayerKey)
+	room.Unlock()
+	ghostGame, rpcErr := call[ghostGameResponse](
+		t, server, "startGhostGame", ghostGameRequest{
+			RoomKey:      created.RoomKey,
+			PlayerKey:    created.PlayerKey,
+			PlayerSecret: created.PlayerSecret,
+		},
+	)
+	require.Nil(t, rpcErr)
+	assert.Equal(t, 1, ghostGame.NumberOfQuestions)
+	require.Len(t, ghostGame.Ghosts, 1)
+	assert.Equal(t, "Alice", ghostGame.Ghosts[0].Name)
+	ghostRoom := openTestRoom(server, ghostGame.RoomKey)
+	require.NotNil(t, ghostRoom)
+	ghostRoom.Lock()
+	defer ghostRoom.Unlock()
+	assert.Equal(t, contest.ClassicMode, ghostRoom.Options().Mode)
+	assert.Equal(t, 10*time.Second, ghostRoom.Options().MaxAnswerTime)
+}
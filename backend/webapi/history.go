@@ -0,0 +1,52 @@
+package webapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/fafeitsch/city-knowledge-contest/backend/contest"
+	"github.com/fafeitsch/city-knowledge-contest/backend/history"
+	"path/filepath"
+	"time"
+)
+
+const defaultLeaderboardLimit = 50
+
+func (r *roomContainer) newRoom() *contest.Room {
+	room := contest.NewRoom(r.seed)
+	room.SetArchive(r.history)
+	room.SetRater(r.ratings)
+	return room
+}
+
+type leaderboardRequest struct {
+	ListFileName string     `json:"listFileName"`
+	From         *time.Time `json:"from"`
+	To           *time.Time `json:"to"`
+	Limit        int        `json:"limit"`
+}
+
+func (r *roomContainer) getLeaderboard(message json.RawMessage, address string) (*rpcRequestContext, error) {
+	request := parseMessage[leaderboardRequest](message)
+	filter := history.Filter{}
+	if request.ListFileName != "" {
+		filter.StreetList = filepath.Base(request.ListFileName)
+	}
+	if request.From != nil {
+		filter.From = *request.From
+	}
+	if request.To != nil {
+		filter.To = *request.To
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && filter.To.Before(filter.From) {
+		return nil, fmt.Errorf("the end of the time range must not be before its start")
+	}
+	limit := request.Limit
+	if limit <= 0 {
+		limit = defaultLeaderboardLimit
+	}
+	return &rpcRequestContext{
+		process: func() (any, error) {
+			return r.applyNicknames(r.history.Leaderboard(filter, limit)), nil
+		},
+	}, nil
+}
@@ -0,0 +1,65 @@
+package webapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoomContainer_hostPermissions(t *testing.T) {
+	server := New(Options{})
+	alice := createTestRoom(t, server, "Alice")
+	bob, rpcErr := call[joinResponse](t, server, "joinRoom", joinRequest{Name: "Bob", RoomKey: alice.RoomKey})
+	require.Nil(t, rpcErr)
+	update := func(playerKey string, playerSecret string) *Error {
+		_, rpcErr := call[updateRoomResponse](
+			t, server, "updateRoom", roomUpdateRequest{
+				RoomKey:      alice.RoomKey,
+				PlayerKey:    playerKey,
+				PlayerSecret: playerSecret,
+			},
+		)
+		return rpcErr
+	}
+	delegate := func(playerKey string, playerSecret string, target string, permissions ...string) *Error {
+		_, rpcErr := call[map[string]any](
+			t, server, "delegatePermissions", delegatePermissionsRequest{
+				RoomKey:      alice.RoomKey,
+				PlayerKey:    playerKey,
+				PlayerSecret: playerSecret,
+				Target:       target,
+				Permissions:  permissions,
+			},
+		)
+		return rpcErr
+	}
+	transfer := func(playerKey string, playerSecret string, target string) *Error {
+		_, rpcErr := call[map[string]any](
+			t, server, "transferHost", transferHostRequest{
+				RoomKey:      alice.RoomKey,
+				PlayerKey:    playerKey,
+				PlayerSecret: playerSecret,
+				Target:       target,
+			},
+		)
+		return rpcErr
+	}
+
+	assert.Nil(t, update(alice.PlayerKey, alice.PlayerSecret))
+	assert.NotNil(t, update(bob.PlayerKey, bob.PlayerSecret))
+	assert.NotNil(t, update(alice.PlayerKey, bob.PlayerSecret))
+	assert.NotNil(t, delegate(bob.PlayerKey, bob.PlayerSecret, bob.PlayerKey, "updateRoom"))
+	assert.NotNil(t, delegate(alice.PlayerKey, alice.PlayerSecret, bob.PlayerKey, "rule"))
+	assert.NotNil(t, delegate(alice.PlayerKey, alice.PlayerSecret, alice.PlayerKey, "updateRoom"))
+	require.Nil(t, delegate(alice.PlayerKey, alice.PlayerSecret, bob.PlayerKey, "updateRoom"))
+	assert.Nil(t, update(bob.PlayerKey, bob.PlayerSecret))
+
+	assert.NotNil(t, transfer(bob.PlayerKey, bob.PlayerSecret, bob.PlayerKey))
+	assert.NotNil(t, transfer(alice.PlayerKey, alice.PlayerSecret, "unknown"))
+	require.Nil(t, transfer(alice.PlayerKey, alice.PlayerSecret, bob.PlayerKey))
+	assert.NotNil(t, delegate(alice.PlayerKey, alice.PlayerSecret, bob.PlayerKey, "updateRoom"))
+	assert.Nil(t, delegate(bob.PlayerKey, bob.PlayerSecret, alice.PlayerKey, "startGame"))
+	assert.NotNil(t, update(alice.PlayerKey, alice.PlayerSecret))
+	assert.Nil(t, update(bob.PlayerKey, bob.PlayerSecret))
+}
@@ -0,0 +1,219 @@
+package webapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/fafeitsch/city-knowledge-contest/backend/contest"
+	"github.com/fafeitsch/city-knowledge-contest/backend/geodata"
+	"log"
+	"net/http"
+	"nhooyr.io/websocket"
+	"nhooyr.io/websocket/wsjson"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+type publicRoom struct {
+	RoomKey      string `json:"roomKey"`
+	Name         string `json:"name"`
+	ListFileName string `json:"listFileName"`
+	ListName     string `json:"listName"`
+	PlayerCount  int    `json:"playerCount"`
+	MaxPlayers   int    `json:"maxPlayers"`
+	Protected    bool   `json:"protected"`
+	Status       string `json:"status"`
+}
+
+type lobby struct {
+	sync.Mutex
+	subscribers map[*websocketNotifier]bool
+	last        []byte
+}
+
+func (r *roomContainer) publicRooms() []publicRoom {
+	r.RLock()
+	rooms := make([]*contest.Room, 0, len(r.openRooms))
+	for _, room := range r.openRooms {
+		rooms = append(rooms, room)
+	}
+	r.RUnlock()
+	result := make([]publicRoom, 0)
+	for _, room := range rooms {
+		room.Lock()
+		options := room.Options()
+		if options.Public && len(room.Players()) > 0 {
+			entry := publicRoom{
+				RoomKey:     room.Key(),
+				Name:        room.Name(),
+				PlayerCount: len(room.Players()),
+				MaxPlayers:  options.MaxPlayers,
+				Protected:   room.Protected(),
+				Status:      string(room.Status()),
+			}
+			if options.StreetList != nil {
+				entry.ListFileName = options.StreetList.FileName
+				entry.ListName = options.StreetList.Name
+			}
+			result = append(result, entry)
+		}
+		room.Unlock()
+	}
+	sort.Slice(
+		result, func(i, j int) bool {
+			if result[i].PlayerCount != result[j].PlayerCount {
+				return result[i].PlayerCount > result[j].PlayerCount
+			}
+			return result[i].RoomKey < result[j].RoomKey
+		},
+	)
+	return result
+}
+
+func (r *roomContainer) listPublicRooms(message json.RawMessage, address string) (*rpcRequestContext, error) {
+	return &rpcRequestContext{
+		process: func() (any, error) {
+			return r.publicRooms(), nil
+		},
+	}, nil
+}
+
+func (r *roomContainer) startLobbyFeed() {
+	go func() {
+		for {
+			time.Sleep(2 * time.Second)
+			r.publishLobby()
+		}
+	}()
+}
+
+func (r *roomContainer) publishLobby() {
+	rooms := r.publicRooms()
+	data, _ := json.Marshal(rooms)
+	r.lobby.Lock()
+	defer r.lobby.Unlock()
+	if bytes.Equal(data, r.lobby.last) {
+		return
+	}
+	r.lobby.last = data
+	for subscriber := range r.lobby.subscribers {
+		go subscriber.write(websocketMessage{Topic: "publicRooms", Payload: rooms})
+	}
+}
+
+func (r *roomContainer) upgradeToLobby(writer http.ResponseWriter, request *http.Request, options Options) error {
+	connection, err := websocket.Accept(
+		writer, request, &websocket.AcceptOptions{InsecureSkipVerify: options.AllowCors},
+	)
+	if err != nil {
+		return fmt.Errorf("could not upgrade to websockets: %v", err)
+	}
+	subscriber := &websocketNotifier{
+		write: func(msg any) {
+			_ = wsjson.Write(request.Context(), connection, msg)
+		},
+	}
+	r.lobby.Lock()
+	r.lobby.subscribers[subscriber] = true
+	r.lobby.Unlock()
+	subscriber.write(websocketMessage{Topic: "publicRooms", Payload: r.publicRooms()})
+	closeContext := connection.CloseRead(request.Context())
+	var pingErr error
+	for pingErr == nil {
+		pingErr = connection.Ping(closeContext)
+		time.Sleep(10 * time.Second)
+	}
+	r.lobby.Lock()
+	delete(r.lobby.subscribers, subscriber)
+	r.lobby.Unlock()
+	_ = connection.Close(websocket.StatusNormalClosure, "")
+	return nil
+}
+
+type quickMatchRequest struct {
+	Name         string `json:"name"`
+	ListFileName string `json:"listFileName"`
+	ProfileToken string `json:"profileToken"`
+}
+
+type quickMatchResponse struct {
+	RoomKey      string `json:"roomKey"`
+	Name         string `json:"name"`
+	PlayerKey    string `json:"playerKey"`
+	PlayerSecret string `json:"playerSecret"`
+	ProfileId    string `json:"profileId,omitempty"`
+	Created      bool   `json:"created"`
+}
+
+func (r *roomContainer) quickMatch(message json.RawMessage, address string) (*rpcRequestContext, error) {
+	request := parseMessage[quickMatchRequest](message)
+	playerProfile, name, err := r.resolveProfile(request.ProfileToken, request.Name)
+	if err != nil {
+		return nil, err
+	}
+	if len(name) == 0 {
+		return nil, fmt.Errorf("a player name must not be empty")
+	}
+	streetList, err := geodata.ReadStreetList(filepath.Base(request.ListFileName))
+	if err != nil {
+		return nil, fmt.Errorf("could not load street list: %s", err)
+	}
+	return &rpcRequestContext{
+		process: func() (any, error) {
+			for _, candidate := range r.publicRooms() {
+				if candidate.Protected || candidate.ListFileName != streetList.FileName {
+					continue
+				}
+				r.RLock()
+				room, ok := r.openRooms[candidate.RoomKey]
+				r.RUnlock()
+				if !ok {
+					continue
+				}
+				room.Lock()
+				running := room.Started() && !room.Finished()
+				if len(room.Players()) == 0 || running || r.tournamentStarted(room.Key()) || room.IsFull() ||
+					room.IsBanned(playerProfile.Id, address) || room.Protected() {
+					room.Unlock()
+					continue
+				}
+				if _, ok := room.PlayerForProfile(playerProfile.Id); ok {
+					room.Unlock()
+					continue
+				}
+				player := room.Join(name, address)
+				room.SetProfile(player.Key, playerProfile.Id)
+				room.Unlock()
+				log.Printf("Player \"%s\" (\"%s\") quick-matched into room \"%s\".", player.Key, player.Name, room.Key())
+				return quickMatchResponse{
+					RoomKey:      room.Key(),
+					Name:         player.Name,
+					PlayerKey:    player.Key,
+					PlayerSecret: player.Secret,
+					ProfileId:    playerProfile.Id,
+				}, nil
+			}
+			room := r.newRoom()
+			player := room.Join(name, address)
+			room.SetProfile(player.Key, playerProfile.Id)
+			options := room.Options()
+			options.StreetList = streetList
+			options.Public = true
+			room.SetOptions(options, player.Key)
+			r.Lock()
+			r.openRooms[room.Key()] = room
+			r.Unlock()
+			log.Printf("Created public room \"%s\" for quick match of player \"%s\" (\"%s\").", room.Key(), player.Key, player.Name)
+			return quickMatchResponse{
+				RoomKey:      room.Key(),
+				Name:         player.Name,
+				PlayerKey:    player.Key,
+				PlayerSecret: player.Secret,
+				ProfileId:    playerProfile.Id,
+				Created:      true,
+			}, nil
+		},
+	}, nil
+}
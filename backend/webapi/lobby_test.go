@@ -0,0 +1,89 @@
+package webapi
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"nhooyr.io/websocket"
+	"nhooyr.io/websocket/wsjson"
+)
+
+const testListFileName = "wuerzburg-altstadt.json"
+
+func TestRoomContainer_quickMatch(t *testing.T) {
+	server := New(Options{})
+	private := createTestRoom(t, server, "Mallory")
+	alice, rpcErr := call[quickMatchResponse](
+		t, server, "quickMatch", quickMatchRequest{Name: "Alice", ListFileName: testListFileName},
+	)
+	require.Nil(t, rpcErr)
+	assert.True(t, alice.Created)
+	assert.NotEqual(t, private.RoomKey, alice.RoomKey)
+	bob, rpcErr := call[quickMatchResponse](
+		t, server, "quickMatch", quickMatchRequest{Name: "Bob", ListFileName: testListFileName},
+	)
+	require.Nil(t, rpcErr)
+	assert.False(t, bob.Created)
+	assert.Equal(t, alice.RoomKey, bob.RoomKey)
+
+	rooms, rpcErr := call[[]publicRoom](t, server, "listPublicRooms", map[string]any{})
+	require.Nil(t, rpcErr)
+	require.Len(t, rooms, 1)
+	assert.Equal(t, alice.RoomKey, rooms[0].RoomKey)
+	assert.Equal(t, 2, rooms[0].PlayerCount)
+	assert.Equal(t, testListFileName, rooms[0].ListFileName)
+	assert.False(t, rooms[0].Protected)
+
+	password := "secret"
+	_, rpcErr = call[updateRoomResponse](
+		t, server, "updateRoom", roomUpdateRequest{
+			RoomKey:      alice.RoomKey,
+			PlayerKey:    alice.PlayerKey,
+			PlayerSecret: alice.PlayerSecret,
+			ListFileName: testListFileName,
+			Public:       true,
+			Password:     &password,
+		},
+	)
+	require.Nil(t, rpcErr)
+	carol, rpcErr := call[quickMatchResponse](
+		t, server, "quickMatch", quickMatchRequest{Name: "Carol", ListFileName: testListFileName},
+	)
+	require.Nil(t, rpcErr)
+	assert.True(t, carol.Created)
+	assert.NotEqual(t, alice.RoomKey, carol.RoomKey)
+
+	_, rpcErr = call[quickMatchResponse](
+		t, server, "quickMatch", quickMatchRequest{Name: "Dave", ListFileName: "unknown.json"},
+	)
+	assert.NotNil(t, rpcErr)
+}
+
+func TestRpcServer_upgradeToLobby(t *testing.T) {
+	server := New(Options{})
+	alice, rpcErr := call[quickMatchResponse](
+		t, server, "quickMatch", quickMatchRequest{Name: "Alice", ListFileName: testListFileName},
+	)
+	require.Nil(t, rpcErr)
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	connection, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(httpServer.URL, "http")+"/lobby", nil)
+	require.NoError(t, err)
+	defer func() { _ = connection.Close(websocket.StatusNormalClosure, "") }()
+	var message struct {
+		Topic   string       `json:"topic"`
+		Payload []publicRoom `json:"payload"`
+	}
+	require.NoError(t, wsjson.Read(ctx, connection, &message))
+	assert.Equal(t, "publicRooms", message.Topic)
+	require.Len(t, message.Payload, 1)
+	assert.Equal(t, alice.RoomKey, message.Payload[0].RoomKey)
+	assert.Equal(t, 1, message.Payload[0].PlayerCount)
+}
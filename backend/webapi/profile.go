@@ -0,0 +1,128 @@
+package webapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/fafeitsch/city-knowledge-contest/backend/history"
+	"github.com/fafeitsch/city-knowledge-contest/backend/profile"
+	"log"
+)
+
+const recentProfileGames = 10
+
+var errUnknownProfile = codedError{code: accessDeniedErrorCode, err: fmt.Errorf("the profile token is not valid")}
+
+func (r *roomContainer) resolveProfile(token string, name string) (profile.Profile, string, error) {
+	if token == "" {
+		return profile.Profile{}, name, nil
+	}
+	playerProfile, ok := r.profiles.Authenticate(token)
+	if !ok {
+		return profile.Profile{}, name, errUnknownProfile
+	}
+	if name == "" {
+		name = playerProfile.Nickname
+	}
+	return playerProfile, name, nil
+}
+
+type createProfileRequest struct {
+	Nickname string `json:"nickname"`
+}
+
+type createProfileResponse struct {
+	profile.Profile
+	Token string `json:"token"`
+}
+
+func (r *roomContainer) createProfile(message json.RawMessage, address string) (*rpcRequestContext, error) {
+	request := parseMessage[createProfileRequest](message)
+	if err := profile.ValidateNickname(request.Nickname); err != nil {
+		return nil, err
+	}
+	return &rpcRequestContext{
+		process: func() (any, error) {
+			created, token, err := r.profiles.Create(request.Nickname)
+			if err != nil {
+				log.Printf("could not persist profile \"%s\": %v", created.Id, err)
+			}
+			return createProfileResponse{Profile: created, Token: token}, nil
+		},
+	}, nil
+}
+
+type profileRequest struct {
+	Token    string `json:"token"`
+	Nickname string `json:"nickname"`
+}
+
+type profileResponse struct {
+	profile.Profile
+	Statistics  history.LeaderboardEntry `json:"statistics"`
+	RecentGames []history.Game           `json:"recentGames"`
+}
+
+func (r *roomContainer) getProfile(message json.RawMessage, address string) (*rpcRequestContext, error) {
+	request := parseMessage[profileRequest](message)
+	playerProfile, ok := r.profiles.Authenticate(request.Token)
+	if !ok {
+		return nil, errUnknownProfile
+	}
+	return &rpcRequestContext{
+		process: func() (any, error) {
+			games := r.history.Games(history.Filter{Profile: playerProfile.Id})
+			if len(games) > recentProfileGames {
+				games = games[len(games)-recentProfileGames:]
+			}
+			statistics := r.history.Statistics(playerProfile.Id)
+			statistics.Name = playerProfile.Nickname
+			return profileResponse{Profile: playerProfile, Statistics: statistics, RecentGames: games}, nil
+		},
+	}, nil
+}
+
+func (r *roomContainer) updateProfile(message json.RawMessage, address string) (*rpcRequestContext, error) {
+	request := parseMessage[profileRequest](message)
+	if _, ok := r.profiles.Authenticate(request.Token); !ok {
+		return nil, errUnknownProfile
+	}
+	if err := profile.ValidateNickname(request.Nickname); err != nil {
+		return nil, err
+	}
+	return &rpcRequestContext{
+		process: func() (any, error) {
+			updated, err := r.profiles.Rename(request.Token, request.Nickname)
+			if err != nil {
+				log.Printf("could not persist profile \"%s\": %v", updated.Id, err)
+			}
+			return updated, nil
+		},
+	}, nil
+}
+
+func (r *roomContainer) deleteProfile(message json.RawMessage, address string) (*rpcRequestContext, error) {
+	request := parseMessage[profileRequest](message)
+	if _, ok := r.profiles.Authenticate(request.Token); !ok {
+		return nil, errUnknownProfile
+	}
+	return &rpcRequestContext{
+		process: func() (any, error) {
+			if err := r.profiles.Delete(request.Token); err != nil {
+				return nil, err
+			}
+			return true, nil
+		},
+	}, nil
+}
+
+func (r *roomContainer) applyNicknames(entries []history.LeaderboardEntry) []history.LeaderboardEntry {
+	for index, entry := range entries {
+		if entry.ProfileId == "" {
+			continue
+		}
+		if playerProfile, ok := r.profiles.Get(entry.ProfileId); ok {
+			entries[index].Name = playerProfile.Nickname
+		}
+	}
+	return entries
+}
@@ -0,0 +1,40 @@
+package webapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/fafeitsch/city-knowledge-contest/backend/rating"
+	"path/filepath"
+)
+
+type ratingsRequest struct {
+	ProfileId    string `json:"profileId"`
+	ListFileName string `json:"listFileName"`
+}
+
+type ratingsResponse struct {
+	ProfileId string          `json:"profileId"`
+	Nickname  string          `json:"nickname"`
+	Ratings   []rating.Rating `json:"ratings"`
+}
+
+func (r *roomContainer) getRatings(message json.RawMessage, address string) (*rpcRequestContext, error) {
+	request := parseMessage[ratingsRequest](message)
+	playerProfile, ok := r.profiles.Get(request.ProfileId)
+	if !ok {
+		return nil, fmt.Errorf("profile with id \"%s\" not found", request.ProfileId)
+	}
+	streetList := ""
+	if request.ListFileName != "" {
+		streetList = filepath.Base(request.ListFileName)
+	}
+	return &rpcRequestContext{
+		process: func() (any, error) {
+			return ratingsResponse{
+				ProfileId: playerProfile.Id,
+				Nickname:  playerProfile.Nickname,
+				Ratings:   r.ratings.Ratings(playerProfile.Id, streetList),
+			}, nil
+		},
+	}, nil
+}
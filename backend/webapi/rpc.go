@@ -3,8 +3,12 @@ package webapi
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/fafeitsch/city-knowledge-contest/backend/challenge"
 	"github.com/fafeitsch/city-knowledge-contest/backend/contest"
 	"github.com/fafeitsch/city-knowledge-contest/backend/geodata"
+	"github.com/fafeitsch/city-knowledge-contest/backend/history"
+	"github.com/fafeitsch/city-knowledge-contest/backend/profile"
+	"github.com/fafeitsch/city-knowledge-contest/backend/rating"
 	"github.com/fafeitsch/city-knowledge-contest/backend/types"
 	"log"
 	"math"
@@ -16,13 +20,23 @@ import (
 	"time"
 )
 
+const maxPauseDuration = 30 * time.Minute
+
 type roomContainer struct {
 	sync.RWMutex
-	openRooms map[string]*contest.Room
-	seed      string
+	openRooms   map[string]*contest.Room
+	seed        string
+	attempts    *attemptLimiter
+	lobby       *lobby
+	history     *history.Store
+	profiles    *profile.Registry
+	ratings     *rating.Ledger
+	daily       *challenge.Daily
+	friends     *challenge.Friends
+	tournaments map[string]*contest.Tournament
 }
 
-type rpcHandler func(message json.RawMessage) (*rpcRequestContext, error)
+type rpcHandler func(message json.RawMessage, address string) (*rpcRequestContext, error)
 
 type rpcRequestContext struct {
 	process func() (any, error)
@@ -30,7 +44,8 @@ type rpcRequestContext struct {
 }
 
 type createRoomRequest struct {
-	Name string `json:"name"`
+	Name         string `json:"name"`
+	ProfileToken string `json:"profileToken"`
 }
 
 type createRoomResponse struct {
@@ -38,6 +53,9 @@ type createRoomResponse struct {
 	RoomKey           string   `json:"roomKey"`
 	PlayerKey         string   `json:"playerKey"`
 	PlayerSecret      string   `json:"playerSecret"`
+	ProfileId         string   `json:"profileId,omitempty"`
+	DisplayToken      string   `json:"displayToken"`
+	ExportToken       string   `json:"exportToken"`
 	NumberOfQuestions int      `json:"numberOfQuestions"`
 	Errors            []string `json:"errors"`
 }
@@ -67,15 +85,20 @@ func (r *roomContainer) isValidTileRequest(req *http.Request) bool {
 	return lat < boundingBox.MaxLat && lat > boundingBox.MinLat && lng < boundingBox.MaxLng && lng > boundingBox.MinLng
 }
 
-func (r *roomContainer) createRoom(message json.RawMessage) (*rpcRequestContext, error) {
+func (r *roomContainer) createRoom(message json.RawMessage, address string) (*rpcRequestContext, error) {
 	request := parseMessage[createRoomRequest](message)
-	if len(request.Name) == 0 {
+	playerProfile, name, err := r.resolveProfile(request.ProfileToken, request.Name)
+	if err != nil {
+		return nil, err
+	}
+	if len(name) == 0 {
 		return nil, fmt.Errorf("a player name must not be empty")
 	}
 	return &rpcRequestContext{
 		process: func() (any, error) {
-			room := contest.NewRoom(r.seed)
-			player := room.Join(request.Name)
+			room := r.newRoom()
+			player := room.Join(name, address)
+			room.SetProfile(player.Key, playerProfile.Id)
 			r.Lock()
 			r.openRooms[room.Key()] = room
 			r.Unlock()
@@ -87,6 +110,9 @@ func (r *roomContainer) createRoom(message json.RawMessage) (*rpcRequestContext,
 				RoomKey:           room.Key(),
 				PlayerKey:         player.Key,
 				PlayerSecret:      player.Secret,
+				ProfileId:         playerProfile.Id,
+				DisplayToken:      room.DisplayToken(),
+				ExportToken:       room.ExportToken(),
 				Errors:            room.ConfigErrors(),
 				ListName:          streetListName,
 				NumberOfQuestions: room.Options().NumberOfQuestions,
@@ -98,23 +124,53 @@ func (r *roomContainer) createRoom(message json.RawMessage) (*rpcRequestContext,
 }
 
 type roomUpdateRequest struct {
-	ListFileName      string `json:"listFileName"`
-	NumberOfQuestions int    `json:"numberOfQuestions"`
-	RoomKey           string `json:"roomKey"`
-	MaxAnswerTimeSec  int    `json:"maxAnswerTimeSec"`
-	PlayerKey         string `json:"playerKey"`
-	PlayerSecret      string `json:"playerSecret"`
+	RoomName          string  `json:"roomName"`
+	Public            bool    `json:"public"`
+	ListFileName      string  `json:"listFileName"`
+	Mode              string  `json:"mode"`
+	NumberOfQuestions int     `json:"numberOfQuestions"`
+	RoomKey           string  `json:"roomKey"`
+	MaxAnswerTimeSec  int     `json:"maxAnswerTimeSec"`
+	KickQuorum        int     `json:"kickQuorum"`
+	GameDurationSec   int     `json:"gameDurationSec"`
+	CoopTarget        int     `json:"coopTarget"`
+	LockIn            bool    `json:"lockIn"`
+	AutoAdvance       bool    `json:"autoAdvance"`
+	ReviewTimeSec     int     `json:"reviewTimeSec"`
+	RefuseLateJoin    bool    `json:"refuseLateJoin"`
+	LateJoinScore     string  `json:"lateJoinScore"`
+	GracePeriodSec    int     `json:"gracePeriodSec"`
+	MaxPlayers        int     `json:"maxPlayers"`
+	MinPlayersToStart int     `json:"minPlayersToStart"`
+	Password          *string `json:"password"`
+	InviteOnly        *bool   `json:"inviteOnly"`
+	PlayerKey         string  `json:"playerKey"`
+	PlayerSecret      string  `json:"playerSecret"`
 }
 
 type updateRoomResponse struct {
-	Errors []string `json:"errors"`
+	Errors            []string `json:"errors"`
+	PasswordProtected bool     `json:"passwordProtected"`
+	InviteOnly        bool     `json:"inviteOnly"`
 }
 
-func (r *roomContainer) updateRoom(message json.RawMessage) (*rpcRequestContext, error) {
+func (r *roomContainer) updateRoom(message json.RawMessage, address string) (*rpcRequestContext, error) {
 	request := parseMessage[roomUpdateRequest](message)
-	room, err := r.validateRoomAndPlayer(request.RoomKey, request.PlayerKey, request.PlayerSecret)
+	room, err := r.validatePermission(
+		request.RoomKey, request.PlayerKey, request.PlayerSecret, contest.UpdateRoomPermission,
+	)
 	if err != nil {
-		return nil, err
+		return &rpcRequestContext{release: unlockRoom(room)}, err
+	}
+	if room.Challenge() != "" {
+		return &rpcRequestContext{release: unlockRoom(room)}, fmt.Errorf(
+			"the settings of challenge room \"%s\" cannot be changed", request.RoomKey,
+		)
+	}
+	if _, ok := r.tournament(request.RoomKey); ok {
+		return &rpcRequestContext{release: unlockRoom(room)}, fmt.Errorf(
+			"the settings of tournament room \"%s\" are defined by its stages", request.RoomKey,
+		)
 	}
 	return &rpcRequestContext{
 		process: func() (any, error) {
@@ -126,15 +182,58 @@ func (r *roomContainer) updateRoom(message json.RawMessage) (*rpcRequestContext,
 					return updateRoomResponse{}, fmt.Errorf("could not load street list: %s", err)
 				}
 			}
+			mode := contest.GameMode(request.Mode)
+			if mode == "" {
+				mode = contest.ClassicMode
+			}
+			if request.KickQuorum == 0 {
+				request.KickQuorum = contest.DefaultKickQuorum
+			}
+			gracePeriod := time.Duration(request.GracePeriodSec) * time.Second
+			if gracePeriod == 0 {
+				gracePeriod = contest.DefaultGracePeriod
+			}
+			if request.MaxPlayers == 0 {
+				request.MaxPlayers = contest.DefaultMaxPlayers
+			}
+			if request.MinPlayersToStart == 0 {
+				request.MinPlayersToStart = 1
+			}
+			lateJoinScore := contest.LateJoinScore(request.LateJoinScore)
+			if lateJoinScore == "" {
+				lateJoinScore = contest.ZeroLateJoinScore
+			}
 			room.SetOptions(
 				contest.RoomOptions{
+					Name:              request.RoomName,
+					Public:            request.Public,
 					StreetList:        streetList,
+					Mode:              mode,
 					NumberOfQuestions: request.NumberOfQuestions,
 					MaxAnswerTime:     time.Duration(request.MaxAnswerTimeSec) * time.Second,
+					GameDuration:      time.Duration(request.GameDurationSec) * time.Second,
+					CoopTarget:        request.CoopTarget,
+					LockIn:            request.LockIn,
+					AutoAdvance:       request.AutoAdvance,
+					ReviewTime:        time.Duration(request.ReviewTimeSec) * time.Second,
+					KickQuorum:        request.KickQuorum,
+					RefuseLateJoin:    request.RefuseLateJoin,
+					LateJoinScore:     lateJoinScore,
+					GracePeriod:       gracePeriod,
+					MaxPlayers:        request.MaxPlayers,
+					MinPlayersToStart: request.MinPlayersToStart,
 				}, request.PlayerKey,
 			)
+			if request.Password != nil {
+				room.SetPassword(*request.Password)
+			}
+			if request.InviteOnly != nil {
+				room.SetInviteOnly(*request.InviteOnly)
+			}
 			return updateRoomResponse{
-				Errors: room.ConfigErrors(),
+				Errors:            room.ConfigErrors(),
+				PasswordProtected: room.HasPassword(),
+				InviteOnly:        room.InviteOnly(),
 			}, nil
 		},
 		release: unlockRoom(room),
@@ -142,34 +241,78 @@ func (r *roomContainer) updateRoom(message json.RawMessage) (*rpcRequestContext,
 }
 
 type joinRequest struct {
-	Name    string `json:"name"`
-	RoomKey string `json:"roomKey"`
+	Name         string `json:"name"`
+	RoomKey      string `json:"roomKey"`
+	Password     string `json:"password"`
+	InviteToken  string `json:"inviteToken"`
+	ProfileToken string `json:"profileToken"`
 }
 
 type joinResponse struct {
 	Name         string `json:"name"`
 	PlayerKey    string `json:"playerKey"`
 	PlayerSecret string `json:"playerSecret"`
+	ProfileId    string `json:"profileId,omitempty"`
 }
 
-func (r *roomContainer) joinRoom(message json.RawMessage) (*rpcRequestContext, error) {
+func (r *roomContainer) joinRoom(message json.RawMessage, address string) (*rpcRequestContext, error) {
 	request := parseMessage[joinRequest](message)
+	if r.attempts.blocked(address) {
+		return nil, errTooManyAttempts
+	}
+	playerProfile, name, err := r.resolveProfile(request.ProfileToken, request.Name)
+	if err != nil {
+		return nil, err
+	}
 	r.RLock()
 	room, ok := r.openRooms[request.RoomKey]
 	r.RUnlock()
 	if !ok {
+		r.attempts.fail(address)
 		return &rpcRequestContext{release: unlockRoom(room)}, fmt.Errorf(
 			"room with key \"%s\" not found", request.RoomKey,
 		)
 	}
+	passwordHash := hashPasswordAttempt(room, request.Password)
 	room.Lock()
+	if room.IsBanned(playerProfile.Id, address) {
+		return &rpcRequestContext{release: unlockRoom(room)}, fmt.Errorf(
+			"you have been banned from room \"%s\"", request.RoomKey,
+		)
+	}
+	if !room.AcceptsPlayers() {
+		return &rpcRequestContext{release: unlockRoom(room)}, fmt.Errorf(
+			"room \"%s\" does not accept players after the game started", request.RoomKey,
+		)
+	}
+	if r.tournamentStarted(request.RoomKey) {
+		return &rpcRequestContext{release: unlockRoom(room)}, fmt.Errorf(
+			"the tournament in room \"%s\" has already started", request.RoomKey,
+		)
+	}
+	if room.IsFull() {
+		return &rpcRequestContext{release: unlockRoom(room)}, codedError{
+			code: roomFullErrorCode,
+			err:  fmt.Errorf("room \"%s\" is full", request.RoomKey),
+		}
+	}
+	if _, ok := room.PlayerForProfile(playerProfile.Id); ok {
+		return &rpcRequestContext{release: unlockRoom(room)}, fmt.Errorf(
+			"your profile is already playing in room \"%s\"", request.RoomKey,
+		)
+	}
+	if err := r.checkAccess(room, passwordHash, request, address); err != nil {
+		return &rpcRequestContext{release: unlockRoom(room)}, err
+	}
 	return &rpcRequestContext{
 		process: func() (any, error) {
-			player := room.Join(request.Name)
+			player := room.Join(name, address)
+			room.SetProfile(player.Key, playerProfile.Id)
 			response := joinResponse{
 				Name:         player.Name,
 				PlayerKey:    player.Key,
 				PlayerSecret: player.Secret,
+				ProfileId:    playerProfile.Id,
 			}
 			log.Printf("Player \"%s\" (\"%s\") joined room \"%s\".", player.Secret, player.Name, room.Key())
 			return response, nil
@@ -178,13 +321,90 @@ func (r *roomContainer) joinRoom(message json.RawMessage) (*rpcRequestContext, e
 	}, nil
 }
 
+func (r *roomContainer) spectateRoom(message json.RawMessage, address string) (*rpcRequestContext, error) {
+	request := parseMessage[joinRequest](message)
+	if r.attempts.blocked(address) {
+		return nil, errTooManyAttempts
+	}
+	r.RLock()
+	room, ok := r.openRooms[request.RoomKey]
+	r.RUnlock()
+	if !ok {
+		r.attempts.fail(address)
+		return nil, fmt.Errorf("room with key \"%s\" not found", request.RoomKey)
+	}
+	passwordHash := hashPasswordAttempt(room, request.Password)
+	room.Lock()
+	if room.IsBanned("", address) {
+		return &rpcRequestContext{release: unlockRoom(room)}, fmt.Errorf(
+			"you have been banned from room \"%s\"", request.RoomKey,
+		)
+	}
+	if err := r.checkAccess(room, passwordHash, request, address); err != nil {
+		return &rpcRequestContext{release: unlockRoom(room)}, err
+	}
+	return &rpcRequestContext{
+		process: func() (any, error) {
+			spectator := room.Spectate(request.Name, address)
+			response := joinResponse{
+				Name:         spectator.Name,
+				PlayerKey:    spectator.Key,
+				PlayerSecret: spectator.Secret,
+			}
+			log.Printf("Spectator \"%s\" (\"%s\") joined room \"%s\".", spectator.Key, spectator.Name, room.Key())
+			return response, nil
+		},
+		release: unlockRoom(room),
+	}, nil
+}
+
+func (r *roomContainer) becomePlayer(message json.RawMessage, address string) (*rpcRequestContext, error) {
+	request := parseMessage[startGameRequest](message)
+	r.RLock()
+	room, ok := r.openRooms[request.RoomKey]
+	r.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("room with key \"%s\" not found", request.RoomKey)
+	}
+	room.Lock()
+	if s, ok := room.FindSpectator(request.PlayerKey); !ok || s.Secret != request.PlayerSecret {
+		return &rpcRequestContext{release: unlockRoom(room)}, fmt.Errorf(
+			"spectator with key \"%s\" is not watching the room or secret is wrong", request.PlayerKey,
+		)
+	}
+	if !room.CanConvertSpectators() {
+		return &rpcRequestContext{release: unlockRoom(room)}, fmt.Errorf(
+			"spectators can only become players between games",
+		)
+	}
+	if r.tournamentStarted(request.RoomKey) {
+		return &rpcRequestContext{release: unlockRoom(room)}, fmt.Errorf(
+			"the tournament in room \"%s\" has already started", request.RoomKey,
+		)
+	}
+	if room.IsFull() {
+		return &rpcRequestContext{release: unlockRoom(room)}, codedError{
+			code: roomFullErrorCode,
+			err:  fmt.Errorf("room \"%s\" is full", request.RoomKey),
+		}
+	}
+	return &rpcRequestContext{
+		process: func() (any, error) {
+			player := room.ConvertToPlayer(request.PlayerKey)
+			log.Printf("Spectator \"%s\" (\"%s\") became a player in room \"%s\".", player.Key, player.Name, room.Key())
+			return joinResponse{Name: player.Name, PlayerKey: player.Key, PlayerSecret: player.Secret}, nil
+		},
+		release: unlockRoom(room),
+	}, nil
+}
+
 type leaveRequest struct {
 	PlayerKey    string `json:"playerKey"`
 	PlayerSecret string `json:"playerSecret"`
 	RoomKey      string `json:"roomKey"`
 }
 
-func (r *roomContainer) leaveGame(message json.RawMessage) (*rpcRequestContext, error) {
+func (r *roomContainer) leaveGame(message json.RawMessage, address string) (*rpcRequestContext, error) {
 	request := parseMessage[leaveRequest](message)
 	room, err := r.validateRoomAndPlayer(request.RoomKey, request.PlayerKey, request.PlayerSecret)
 	if err != nil {
@@ -207,25 +427,31 @@ type kickRequest struct {
 	Target       string `json:"target"`
 }
 
-func (r *roomContainer) kickPlayer(message json.RawMessage) (*rpcRequestContext, error) {
+func (r *roomContainer) kickPlayer(message json.RawMessage, address string) (*rpcRequestContext, error) {
 	request := parseMessage[kickRequest](message)
 	room, err := r.validateRoomAndPlayer(request.RoomKey, request.PlayerKey, request.PlayerSecret)
 	if err != nil {
 		return &rpcRequestContext{release: unlockRoom(room)}, err
 	}
+	if room.IsHost(request.Target) || request.Target == request.PlayerKey {
+		return &rpcRequestContext{release: unlockRoom(room)}, fmt.Errorf(
+			"player with key \"%s\" cannot be kicked", request.Target,
+		)
+	}
 	return &rpcRequestContext{
 		process: func() (any, error) {
 			if _, ok := room.FindPlayer(request.Target); !ok {
 				return map[string]any{}, nil
 			}
-			kicked := room.Kick(request.Target, request.PlayerKey)
+			kicked := room.VoteKick(request.Target, request.PlayerKey)
 			log.Printf(
-				"Player \"%s\"  kicked player \"%s\" from room \"%s\".",
+				"Player \"%s\" voted to kick player \"%s\" from room \"%s\", kicked: %v.",
 				request.PlayerKey,
 				request.Target,
 				request.RoomKey,
+				kicked,
 			)
-			return kicked, nil
+			return map[string]bool{"kicked": kicked}, nil
 		},
 		release: unlockRoom(room),
 	}, nil
@@ -237,18 +463,31 @@ type startGameRequest struct {
 	RoomKey      string `json:"roomKey"`
 }
 
-func (r *roomContainer) startGame(message json.RawMessage) (*rpcRequestContext, error) {
+func (r *roomContainer) startGame(message json.RawMessage, address string) (*rpcRequestContext, error) {
 	request := parseMessage[startGameRequest](message)
-	room, err := r.validateRoomAndPlayer(request.RoomKey, request.PlayerKey, request.PlayerSecret)
+	room, err := r.validatePermission(
+		request.RoomKey, request.PlayerKey, request.PlayerSecret, contest.StartGamePermission,
+	)
 	if err != nil {
 		return &rpcRequestContext{release: unlockRoom(room)}, err
 	}
+	if room.Started() {
+		return &rpcRequestContext{release: unlockRoom(room)}, fmt.Errorf(
+			"the game has already been started, request a rematch to play again",
+		)
+	}
 	if len(room.ConfigErrors()) > 0 {
 		return &rpcRequestContext{release: unlockRoom(room)}, fmt.Errorf(
 			"the room is not yet configured correctly, "+
 				"it still has the following config errors: %v", room.ConfigErrors(),
 		)
 	}
+	if !room.HasEnoughPlayers() {
+		return &rpcRequestContext{release: unlockRoom(room)}, fmt.Errorf(
+			"at least %d players are needed to start the game, but only %d joined",
+			room.Options().MinPlayersToStart, len(room.Players()),
+		)
+	}
 	return &rpcRequestContext{
 		process: func() (any, error) {
 			room.Play(request.PlayerKey)
@@ -258,6 +497,46 @@ func (r *roomContainer) startGame(message json.RawMessage) (*rpcRequestContext,
 	}, nil
 }
 
+type rematchRequest struct {
+	PlayerKey    string `json:"playerKey"`
+	PlayerSecret string `json:"playerSecret"`
+	RoomKey      string `json:"roomKey"`
+	KeepScores   bool   `json:"keepScores"`
+}
+
+func (r *roomContainer) rematch(message json.RawMessage, address string) (*rpcRequestContext, error) {
+	request := parseMessage[rematchRequest](message)
+	room, err := r.validatePermission(
+		request.RoomKey, request.PlayerKey, request.PlayerSecret, contest.StartGamePermission,
+	)
+	if err != nil {
+		return &rpcRequestContext{release: unlockRoom(room)}, err
+	}
+	if room.Challenge() != "" {
+		return &rpcRequestContext{release: unlockRoom(room)}, fmt.Errorf(
+			"a challenge can only be played once per room",
+		)
+	}
+	if _, ok := r.tournament(request.RoomKey); ok {
+		return &rpcRequestContext{release: unlockRoom(room)}, fmt.Errorf(
+			"the next game of a tournament is started by advancing the tournament",
+		)
+	}
+	if !room.CanRematch() {
+		return &rpcRequestContext{release: unlockRoom(room)}, fmt.Errorf(
+			"a rematch can only be requested after a game has ended",
+		)
+	}
+	return &rpcRequestContext{
+		process: func() (any, error) {
+			room.Rematch(request.PlayerKey, request.KeepScores)
+			log.Printf("Player \"%s\" requested a rematch in room \"%s\".", request.PlayerKey, room.Key())
+			return map[string]any{}, nil
+		},
+		release: unlockRoom(room),
+	}, nil
+}
+
 func (r *roomContainer) validateRoomAndPlayer(roomKey string, playerKey string, secret string) (*contest.Room, error) {
 	r.RLock()
 	room, ok := r.openRooms[roomKey]
@@ -267,7 +546,20 @@ func (r *roomContainer) validateRoomAndPlayer(roomKey string, playerKey string,
 	}
 	room.Lock()
 	if p, ok := room.FindPlayer(playerKey); !ok || p.Secret != secret {
-		return nil, fmt.Errorf("player with key \"%s\" has not joined the room yet or secret is wrong", playerKey)
+		return room, fmt.Errorf("player with key \"%s\" has not joined the room yet or secret is wrong", playerKey)
+	}
+	return room, nil
+}
+
+func (r *roomContainer) validatePermission(
+	roomKey string, playerKey string, secret string, permission contest.Permission,
+) (*contest.Room, error) {
+	room, err := r.validateRoomAndPlayer(roomKey, playerKey, secret)
+	if err != nil {
+		return room, err
+	}
+	if !room.HasPermission(playerKey, permission) {
+		return room, fmt.Errorf("player with key \"%s\" lacks the permission \"%s\"", playerKey, permission)
 	}
 	return room, nil
 }
@@ -277,17 +569,35 @@ type guessRequest struct {
 	PlayerSecret string     `json:"playerSecret"`
 	RoomKey      string     `json:"roomKey"`
 	Guess        [2]float64 `json:"guess"`
+	Lock         bool       `json:"lock"`
 }
 
-func (r *roomContainer) answerQuestion(message json.RawMessage) (*rpcRequestContext, error) {
+func (r *roomContainer) answerQuestion(message json.RawMessage, address string) (*rpcRequestContext, error) {
 	request := parseMessage[guessRequest](message)
 	room, err := r.validateRoomAndPlayer(request.RoomKey, request.PlayerKey, request.PlayerSecret)
 	if err != nil {
 		return &rpcRequestContext{release: unlockRoom(room)}, err
 	}
+	if room.Paused() {
+		return &rpcRequestContext{release: unlockRoom(room)}, fmt.Errorf("question cannot be answered because the game is paused")
+	}
 	if !room.HasActiveQuestion(request.PlayerKey) {
 		return &rpcRequestContext{release: unlockRoom(room)}, fmt.Errorf("question cannot be answered because there is no active question or player has already answered it")
 	}
+	if room.Options().LockIn {
+		return &rpcRequestContext{
+			process: func() (any, error) {
+				room.SubmitGuess(
+					request.PlayerKey, types.Coordinate{
+						Lat: request.Guess[0],
+						Lng: request.Guess[1],
+					}, request.Lock,
+				)
+				return map[string]bool{"locked": request.Lock}, nil
+			},
+			release: unlockRoom(room),
+		}, nil
+	}
 	return &rpcRequestContext{
 		process: func() (any, error) {
 			result, err := room.AnswerQuestion(
@@ -305,9 +615,11 @@ func (r *roomContainer) answerQuestion(message json.RawMessage) (*rpcRequestCont
 	}, nil
 }
 
-func (r *roomContainer) advanceGame(message json.RawMessage) (*rpcRequestContext, error) {
+func (r *roomContainer) advanceGame(message json.RawMessage, address string) (*rpcRequestContext, error) {
 	request := parseMessage[startGameRequest](message)
-	room, err := r.validateRoomAndPlayer(request.RoomKey, request.PlayerKey, request.PlayerSecret)
+	room, err := r.validatePermission(
+		request.RoomKey, request.PlayerKey, request.PlayerSecret, contest.AdvanceGamePermission,
+	)
 	if err != nil {
 		return &rpcRequestContext{release: unlockRoom(room)}, err
 	}
@@ -324,7 +636,161 @@ func (r *roomContainer) advanceGame(message json.RawMessage) (*rpcRequestContext
 	}, err
 }
 
-func listStreetListFiles(message json.RawMessage) (*rpcRequestContext, error) {
+func (r *roomContainer) voteReady(message json.RawMessage, address string) (*rpcRequestContext, error) {
+	request := parseMessage[startGameRequest](message)
+	room, err := r.validateRoomAndPlayer(request.RoomKey, request.PlayerKey, request.PlayerSecret)
+	if err != nil {
+		return &rpcRequestContext{release: unlockRoom(room)}, err
+	}
+	if !room.CanBeAdvanced() {
+		return &rpcRequestContext{release: unlockRoom(room)}, fmt.Errorf(
+			"the room \"%s\" is not waiting for the next question", request.RoomKey,
+		)
+	}
+	return &rpcRequestContext{
+		process: func() (any, error) {
+			room.VoteReady(request.PlayerKey)
+			return map[string]any{}, nil
+		}, release: unlockRoom(room),
+	}, nil
+}
+
+func (r *roomContainer) getGameSummary(message json.RawMessage, address string) (*rpcRequestContext, error) {
+	request := parseMessage[startGameRequest](message)
+	room, err := r.validateRoomAndPlayer(request.RoomKey, request.PlayerKey, request.PlayerSecret)
+	if err != nil {
+		return &rpcRequestContext{release: unlockRoom(room)}, err
+	}
+	summary, ok := room.Summary()
+	if !ok {
+		return &rpcRequestContext{release: unlockRoom(room)}, fmt.Errorf(
+			"no game has been finished in room \"%s\" yet", request.RoomKey,
+		)
+	}
+	return &rpcRequestContext{
+		process: func() (any, error) {
+			return summary, nil
+		}, release: unlockRoom(room),
+	}, nil
+}
+
+func (r *roomContainer) pauseGame(message json.RawMessage, address string) (*rpcRequestContext, error) {
+	request := parseMessage[startGameRequest](message)
+	room, err := r.validatePermission(
+		request.RoomKey, request.PlayerKey, request.PlayerSecret, contest.PauseGamePermission,
+	)
+	if err != nil {
+		return &rpcRequestContext{release: unlockRoom(room)}, err
+	}
+	if !room.CanBePaused() {
+		return &rpcRequestContext{release: unlockRoom(room)}, fmt.Errorf(
+			"the room \"%s\" cannot be paused", request.RoomKey,
+		)
+	}
+	return &rpcRequestContext{
+		process: func() (any, error) {
+			room.Pause(request.PlayerKey)
+			log.Printf("Player \"%s\" paused room \"%s\".", request.PlayerKey, request.RoomKey)
+			return map[string]any{}, nil
+		}, release: unlockRoom(room),
+	}, nil
+}
+
+func (r *roomContainer) resumeGame(message json.RawMessage, address string) (*rpcRequestContext, error) {
+	request := parseMessage[startGameRequest](message)
+	room, err := r.validatePermission(
+		request.RoomKey, request.PlayerKey, request.PlayerSecret, contest.PauseGamePermission,
+	)
+	if err != nil {
+		return &rpcRequestContext{release: unlockRoom(room)}, err
+	}
+	if !room.Paused() {
+		return &rpcRequestContext{release: unlockRoom(room)}, fmt.Errorf(
+			"the room \"%s\" is not paused", request.RoomKey,
+		)
+	}
+	return &rpcRequestContext{
+		process: func() (any, error) {
+			room.Resume(request.PlayerKey)
+			log.Printf("Player \"%s\" resumed room \"%s\".", request.PlayerKey, request.RoomKey)
+			return map[string]any{}, nil
+		}, release: unlockRoom(room),
+	}, nil
+}
+
+type transferHostRequest struct {
+	PlayerKey    string `json:"playerKey"`
+	PlayerSecret string `json:"playerSecret"`
+	RoomKey      string `json:"roomKey"`
+	Target       string `json:"target"`
+}
+
+func (r *roomContainer) transferHost(message json.RawMessage, address string) (*rpcRequestContext, error) {
+	request := parseMessage[transferHostRequest](message)
+	room, err := r.validateRoomAndPlayer(request.RoomKey, request.PlayerKey, request.PlayerSecret)
+	if err != nil {
+		return &rpcRequestContext{release: unlockRoom(room)}, err
+	}
+	if !room.IsHost(request.PlayerKey) {
+		return &rpcRequestContext{release: unlockRoom(room)}, fmt.Errorf("only the host can transfer the host role")
+	}
+	if _, ok := room.FindPlayer(request.Target); !ok {
+		return &rpcRequestContext{release: unlockRoom(room)}, fmt.Errorf(
+			"player with key \"%s\" has not joined the room", request.Target,
+		)
+	}
+	return &rpcRequestContext{
+		process: func() (any, error) {
+			room.TransferHost(request.Target)
+			log.Printf(
+				"Player \"%s\" transferred the host role of room \"%s\" to \"%s\".",
+				request.PlayerKey,
+				request.RoomKey,
+				request.Target,
+			)
+			return map[string]any{}, nil
+		}, release: unlockRoom(room),
+	}, nil
+}
+
+type delegatePermissionsRequest struct {
+	PlayerKey    string   `json:"playerKey"`
+	PlayerSecret string   `json:"playerSecret"`
+	RoomKey      string   `json:"roomKey"`
+	Target       string   `json:"target"`
+	Permissions  []string `json:"permissions"`
+}
+
+func (r *roomContainer) delegatePermissions(message json.RawMessage, address string) (*rpcRequestContext, error) {
+	request := parseMessage[delegatePermissionsRequest](message)
+	room, err := r.validateRoomAndPlayer(request.RoomKey, request.PlayerKey, request.PlayerSecret)
+	if err != nil {
+		return &rpcRequestContext{release: unlockRoom(room)}, err
+	}
+	if !room.IsHost(request.PlayerKey) {
+		return &rpcRequestContext{release: unlockRoom(room)}, fmt.Errorf("only the host can delegate permissions")
+	}
+	if _, ok := room.FindPlayer(request.Target); !ok || room.IsHost(request.Target) {
+		return &rpcRequestContext{release: unlockRoom(room)}, fmt.Errorf(
+			"player with key \"%s\" cannot become a co-host", request.Target,
+		)
+	}
+	permissions := make([]contest.Permission, 0, len(request.Permissions))
+	for _, permission := range request.Permissions {
+		if !contest.IsPermission(permission) {
+			return &rpcRequestContext{release: unlockRoom(room)}, fmt.Errorf("unknown permission \"%s\"", permission)
+		}
+		permissions = append(permissions, contest.Permission(permission))
+	}
+	return &rpcRequestContext{
+		process: func() (any, error) {
+			room.DelegatePermissions(request.Target, permissions)
+			return map[string]any{"permissions": permissions}, nil
+		}, release: unlockRoom(room),
+	}, nil
+}
+
+func listStreetListFiles(message json.RawMessage, address string) (*rpcRequestContext, error) {
 	return &rpcRequestContext{
 		process: func() (any, error) {
 			return geodata.ReadStreetLists()
@@ -351,19 +817,32 @@ func (r *roomContainer) startRoomCleaner() {
 		for {
 			time.Sleep(time.Second * 5)
 			r.cleanRooms()
+			r.attempts.prune()
 		}
 	}()
 }
 
 func (r *roomContainer) cleanRooms() {
-	r.Lock()
-	defer r.Unlock()
-	now := time.Now()
+	r.RLock()
+	rooms := make(map[string]*contest.Room, len(r.openRooms))
 	for key, room := range r.openRooms {
-		if room.Finished() || now.Sub(room.Creation()) > time.Hour*24 {
-			log.Printf("cleaning room %s", key)
-			_ = r.openRooms[key].Close()
-			delete(r.openRooms, key)
+		rooms[key] = room
+	}
+	r.RUnlock()
+	now := time.Now()
+	for key, room := range rooms {
+		room.Lock()
+		pausedTooLong := room.Paused() && now.Sub(room.PausedSince()) > maxPauseDuration
+		idle := room.Idle(now)
+		room.Unlock()
+		if !idle && !pausedTooLong {
+			continue
 		}
+		log.Printf("cleaning room %s", key)
+		_ = room.Close()
+		r.Lock()
+		delete(r.openRooms, key)
+		delete(r.tournaments, key)
+		r.Unlock()
 	}
 }
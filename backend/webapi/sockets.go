@@ -1,18 +1,24 @@
 package webapi
 
 import (
+	"context"
 	"fmt"
 	"github.com/fafeitsch/city-knowledge-contest/backend/contest"
+	"github.com/fafeitsch/city-knowledge-contest/backend/types"
 	"log"
 	"net/http"
 	"nhooyr.io/websocket"
 	"nhooyr.io/websocket/wsjson"
+	"strconv"
 	"strings"
 	"time"
 )
 
+const pingTimeout = 5 * time.Second
+
 func (r *roomContainer) upgradeToWebSocket(writer http.ResponseWriter, request *http.Request, options Options) error {
-	parts := strings.Split(request.RequestURI, "/")
+	parts := strings.Split(request.URL.Path, "/")
+	since, sinceErr := strconv.Atoi(request.URL.Query().Get("since"))
 	r.RLock()
 	room, roomExists := r.openRooms[parts[2]]
 	r.RUnlock()
@@ -22,8 +28,22 @@ func (r *roomContainer) upgradeToWebSocket(writer http.ResponseWriter, request *
 	}
 	//secret := request.Header.Get("ckc-player-secret")
 	room.Lock()
-	player, _ := room.FindPlayer(parts[3])
+	player, ok := room.FindPlayer(parts[3])
+	spectator := false
+	if !ok {
+		player, ok = room.FindSpectator(parts[3])
+		spectator = ok
+	}
+	banned := ok && room.IsBanned(player.Profile, clientAddress(request))
 	room.Unlock()
+	if banned {
+		writer.WriteHeader(http.StatusForbidden)
+		return nil
+	}
+	if !ok {
+		writer.WriteHeader(http.StatusNotFound)
+		return nil
+	}
 	//if !ok || player.Secret != secret {
 	//	writer.WriteHeader(http.StatusUnauthorized)
 	//	return nil
@@ -39,70 +59,127 @@ func (r *roomContainer) upgradeToWebSocket(writer http.ResponseWriter, request *
 			_ = wsjson.Write(request.Context(), connection, msg)
 		},
 	}
-	player.Notifier = notifier
-	existingPlayers := room.Players()
-	players := make([]playerInfo, 0, len(existingPlayers))
-	for _, p := range existingPlayers {
-		players = append(
-			players, playerInfo{
-				Name:      p.Name,
-				PlayerKey: p.Key,
+	room.Lock()
+	room.Connect(player.Key, notifier, clientAddress(request))
+	if sinceErr == nil && room.CanReplay(since) {
+		sequence := room.Sequence()
+		replay := room.Replay(player.Key, since)
+		room.Unlock()
+		_ = wsjson.Write(
+			request.Context(), connection, websocketMessage{
+				Topic:   "sessionResumed",
+				Payload: map[string]any{"since": since, "sequence": sequence},
 			},
 		)
+		replay(notifier)
+		log.Printf("Resumed websocket session of player \"%s\" (\"%s\") from sequence %d.", player.Key, player.Name, since)
+		return keepAlive(request, connection, room, player, notifier)
+	}
+	players := convertPlayers(room.Players())
+	spectators := convertPlayers(room.Spectators())
+	state := room.State()
+	remaining := time.Duration(0)
+	if !state.Deadline.IsZero() {
+		remaining = time.Until(state.Deadline)
 	}
-	room.Lock()
 	_ = wsjson.Write(
 		request.Context(), connection, websocketMessage{
 			Topic: "successfullyJoined",
 			Payload: initialJoinMessage{
-				Players: players,
-				Options: convertRoomOptions(room.Options(), ""),
-				Started: room.Started(),
+				Players:        players,
+				Options:        convertRoomOptions(room.Options(), ""),
+				Started:        room.Started(),
+				Host:           room.Host(),
+				Spectator:      spectator,
+				Spectators:     spectators,
+				Scoreboard:     state.Scoreboard,
+				QuestionNumber: state.QuestionNumber,
+				RemainingSec:   int(remaining / time.Second),
+				Sequence:       room.Sequence(),
 			},
 		},
 	)
 	room.Unlock()
 	if room.HasActiveQuestion(player.Key) {
 		room.Lock()
-		notifier.NotifyQuestion(room.Question())
+		notifier.NotifyQuestion(room.Question(player.Key))
 		room.Unlock()
 	}
 	log.Printf("Established websocket connection to player \"%s\" (\"%s\").", player.Key, player.Name)
+	return keepAlive(request, connection, room, player, notifier)
+}
+
+func keepAlive(
+	request *http.Request, connection *websocket.Conn, room *contest.Room, player *contest.Player, notifier contest.Notifier,
+) error {
 	closeContext := connection.CloseRead(request.Context())
 	var pingErr error
 	for pingErr == nil {
-		pingErr = connection.Ping(closeContext)
-		time.Sleep(10 * time.Second)
+		pingContext, cancel := context.WithTimeout(closeContext, pingTimeout)
+		pingErr = connection.Ping(pingContext)
+		cancel()
+		if pingErr == nil {
+			room.Lock()
+			room.Heartbeat(player.Key, notifier)
+			room.Unlock()
+			time.Sleep(10 * time.Second)
+		}
 	}
 	log.Printf("Connection to player \"%s\" (\"%s\") lost: %v", player.Key, player.Name, pingErr)
+	room.Lock()
+	room.Disconnect(player.Key, notifier)
+	room.Unlock()
 	_ = connection.Close(websocket.StatusNormalClosure, "")
 	return nil
 }
 
 type websocketMessage struct {
-	Topic   string `json:"topic"`
-	Payload any    `json:"payload"`
+	Sequence int    `json:"seq,omitempty"`
+	Topic    string `json:"topic"`
+	Payload  any    `json:"payload"`
 }
 
 type initialJoinMessage struct {
-	Players []playerInfo      `json:"players"`
-	Options roomUpdateMessage `json:"options"`
-	Started bool              `json:"started"`
+	Players        []playerInfo              `json:"players"`
+	Options        roomUpdateMessage         `json:"options"`
+	Started        bool                      `json:"started"`
+	Host           string                    `json:"host"`
+	Spectator      bool                      `json:"spectator"`
+	Spectators     []playerInfo              `json:"spectators"`
+	Scoreboard     []contest.ScoreboardEntry `json:"scoreboard"`
+	QuestionNumber int                       `json:"questionNumber"`
+	RemainingSec   int                       `json:"remainingSec"`
+	Sequence       int                       `json:"sequence"`
 }
 
 type playerInfo struct {
 	Name      string `json:"name"`
 	PlayerKey string `json:"playerKey"`
+	Connected bool   `json:"connected"`
 }
 
 type roomUpdateMessage struct {
+	RoomName          string         `json:"roomName"`
+	Public            bool           `json:"public"`
 	ListFileName      string         `json:"listFileName"`
 	BoundingBox       *[2][2]float64 `json:"boundingBox,omitempty"`
 	Center            [2]float64     `json:"center,omitempty"`
 	MinZoom           int            `json:"minZoom"`
 	MaxZoom           int            `json:"maxZoom"`
+	Mode              string         `json:"mode"`
 	NumberOfQuestions int            `json:"numberOfQuestions"`
 	MaxAnswerTimeSec  int            `json:"maxAnswerTimeSec"`
+	KickQuorum        int            `json:"kickQuorum"`
+	GameDurationSec   int            `json:"gameDurationSec"`
+	CoopTarget        int            `json:"coopTarget"`
+	LockIn            bool           `json:"lockIn"`
+	AutoAdvance       bool           `json:"autoAdvance"`
+	ReviewTimeSec     int            `json:"reviewTimeSec"`
+	RefuseLateJoin    bool           `json:"refuseLateJoin"`
+	LateJoinScore     string         `json:"lateJoinScore"`
+	GracePeriodSec    int            `json:"gracePeriodSec"`
+	MaxPlayers        int            `json:"maxPlayers"`
+	MinPlayersToStart int            `json:"minPlayersToStart"`
 	PlayerKey         string         `json:"playerKey,omitempty"`
 	Errors            []string       `json:"errors"`
 }
@@ -111,6 +188,18 @@ type websocketNotifier struct {
 	write func(msg any)
 }
 
+func (w *websocketNotifier) WithSequence(sequence int) contest.Notifier {
+	return &websocketNotifier{
+		write: func(msg any) {
+			if message, ok := msg.(websocketMessage); ok {
+				message.Sequence = sequence
+				msg = message
+			}
+			w.write(msg)
+		},
+	}
+}
+
 func (w *websocketNotifier) NotifyPlayerJoined(name string, key string) {
 	payload := map[string]string{"name": name, "playerKey": key}
 	w.write(websocketMessage{Topic: "playerJoined", Payload: payload})
@@ -136,6 +225,21 @@ func (w *websocketNotifier) NotifyGameStarted(playerKey string) {
 	w.write(websocketMessage{Topic: "gameStarted", Payload: message})
 }
 
+func (w *websocketNotifier) NotifyGameDeadline(remaining time.Duration) {
+	message := map[string]any{"remainingSec": int(remaining / time.Second)}
+	w.write(websocketMessage{Topic: "gameDeadline", Payload: message})
+}
+
+func (w *websocketNotifier) NotifyGamePaused(playerKey string) {
+	message := map[string]any{"playerKey": playerKey}
+	w.write(websocketMessage{Topic: "gamePaused", Payload: message})
+}
+
+func (w *websocketNotifier) NotifyGameResumed(playerKey string) {
+	message := map[string]any{"playerKey": playerKey}
+	w.write(websocketMessage{Topic: "gameResumed", Payload: message})
+}
+
 func (w *websocketNotifier) NotifyQuestionCountdown(followUps int, questionNumber int) {
 	message := map[string]any{"followUps": followUps, "questionNumber": questionNumber}
 	w.write(websocketMessage{Topic: "questionCountdown", Payload: message})
@@ -158,17 +262,33 @@ func (w *websocketNotifier) NotifyQuestionResults(result contest.QuestionResult)
 			result.Solution.Lat,
 			result.Solution.Lng,
 		},
-		"delta":          result.PointDelta,
-		"points":         result.Points,
-		"questionNumber": result.QuestionNumber,
+		"delta":           result.PointDelta,
+		"points":          result.Points,
+		"questionNumber":  result.QuestionNumber,
+		"solvedQuestions": result.SolvedQuestions,
+		"guesses":         convertGuesses(result.Guesses),
 	}
 	w.write(websocketMessage{Topic: "questionFinished", Payload: message})
 }
 
-func (w *websocketNotifier) NotifyGameEnded(reason string, result map[string]int) {
+func (w *websocketNotifier) NotifyNextQuestionIn(remaining time.Duration) {
+	message := map[string]any{"remainingSec": int(remaining / time.Second)}
+	w.write(websocketMessage{Topic: "nextQuestionIn", Payload: message})
+}
+
+func (w *websocketNotifier) NotifyPlayerReady(playerKey string) {
+	message := map[string]any{"playerKey": playerKey}
+	w.write(websocketMessage{Topic: "playerReady", Payload: message})
+}
+
+func (w *websocketNotifier) NotifyGameEnded(reason string, result map[string]int, summary contest.GameSummary) {
 	message := map[string]any{
-		"reason": reason,
-		"result": result,
+		"reason":  reason,
+		"result":  result,
+		"summary": summary,
+	}
+	if len(summary.RatingChanges) > 0 {
+		message["ratingChanges"] = summary.RatingChanges
 	}
 	w.write(websocketMessage{Topic: "gameEnded", Payload: message})
 }
@@ -178,11 +298,77 @@ func (w *websocketNotifier) NotifyPlayerAnswered(playerKey string, points int) {
 	w.write(websocketMessage{Topic: "playerAnswered", Payload: message})
 }
 
+func (w *websocketNotifier) NotifyPlayerGuessed(playerKey string, locked bool) {
+	message := map[string]any{"playerKey": playerKey, "locked": locked}
+	w.write(websocketMessage{Topic: "playerAnswered", Payload: message})
+}
+
 func (w *websocketNotifier) NotifyPlayerKicked(playerKey string, name string, initiator string) {
 	message := map[string]any{"playerKey": playerKey, "name": name, "initiator": initiator}
 	w.write(websocketMessage{Topic: "playerKicked", Payload: message})
 }
 
+func (w *websocketNotifier) NotifyKickVoteStarted(vote contest.KickVote) {
+	w.write(websocketMessage{Topic: "kickVoteStarted", Payload: vote})
+}
+
+func (w *websocketNotifier) NotifyKickVoteUpdated(vote contest.KickVote) {
+	w.write(websocketMessage{Topic: "kickVoteUpdated", Payload: vote})
+}
+
+func (w *websocketNotifier) NotifyPlayerPresenceChanged(playerKey string, connected bool) {
+	message := map[string]any{"playerKey": playerKey, "connected": connected}
+	w.write(websocketMessage{Topic: "playerPresenceChanged", Payload: message})
+}
+
+func (w *websocketNotifier) NotifyRematch(playerKey string, game int, points map[string]int) {
+	message := map[string]any{"playerKey": playerKey, "game": game, "points": points}
+	w.write(websocketMessage{Topic: "rematch", Payload: message})
+}
+
+func (w *websocketNotifier) NotifyChallengeBeaten(result contest.ChallengeResult) {
+	w.write(websocketMessage{Topic: "challengeBeaten", Payload: result})
+}
+
+func (w *websocketNotifier) NotifyDailyStanding(standing contest.DailyStanding) {
+	w.write(websocketMessage{Topic: "dailyStanding", Payload: standing})
+}
+
+func (w *websocketNotifier) NotifyTournamentStage(stage contest.TournamentStage) {
+	w.write(websocketMessage{Topic: "tournamentStage", Payload: stage})
+}
+
+func (w *websocketNotifier) NotifyTournamentStandings(standings []contest.TournamentStanding) {
+	w.write(websocketMessage{Topic: "tournamentStandings", Payload: standings})
+}
+
+func (w *websocketNotifier) NotifyHostChanged(playerKey string, name string) {
+	message := map[string]any{"playerKey": playerKey, "name": name}
+	w.write(websocketMessage{Topic: "hostChanged", Payload: message})
+}
+
+func convertPlayers(players []contest.Player) []playerInfo {
+	result := make([]playerInfo, 0, len(players))
+	for _, p := range players {
+		result = append(
+			result, playerInfo{
+				Name:      p.Name,
+				PlayerKey: p.Key,
+				Connected: p.Connected(),
+			},
+		)
+	}
+	return result
+}
+
+func convertGuesses(guesses map[string]types.Coordinate) map[string][2]float64 {
+	result := make(map[string][2]float64, len(guesses))
+	for key, guess := range guesses {
+		result[key] = [2]float64{guess.Lat, guess.Lng}
+	}
+	return result
+}
+
 func convertRoomOptions(options contest.RoomOptions, playerKey string) roomUpdateMessage {
 	listName := ""
 	if options.StreetList != nil {
@@ -205,12 +391,26 @@ func convertRoomOptions(options contest.RoomOptions, playerKey string) roomUpdat
 		maxZoom = mapOptions.MaxZoom
 	}
 	message := roomUpdateMessage{
+		RoomName:          options.Name,
+		Public:            options.Public,
 		ListFileName:      listName,
 		BoundingBox:       boundingBox,
 		Center:            center,
 		MinZoom:           minZoom,
 		MaxZoom:           maxZoom,
+		Mode:              string(options.Mode),
 		MaxAnswerTimeSec:  int(options.MaxAnswerTime / time.Second),
+		KickQuorum:        options.KickQuorum,
+		GameDurationSec:   int(options.GameDuration / time.Second),
+		CoopTarget:        options.CoopTarget,
+		LockIn:            options.LockIn,
+		AutoAdvance:       options.AutoAdvance,
+		ReviewTimeSec:     int(options.ReviewTime / time.Second),
+		RefuseLateJoin:    options.RefuseLateJoin,
+		LateJoinScore:     string(options.LateJoinScore),
+		GracePeriodSec:    int(options.GracePeriod / time.Second),
+		MaxPlayers:        options.MaxPlayers,
+		MinPlayersToStart: options.MinPlayersToStart,
 		NumberOfQuestions: options.NumberOfQuestions,
 		PlayerKey:         playerKey,
 		Errors:            options.Errors(),
@@ -0,0 +1,188 @@
+package webapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/fafeitsch/city-knowledge-contest/backend/contest"
+	"github.com/fafeitsch/city-knowledge-contest/backend/geodata"
+	"log"
+	"path/filepath"
+	"time"
+)
+
+const (
+	maxTournamentStages       = 20
+	defaultStageQuestions     = 10
+	defaultStageAnswerTimeSec = 120
+)
+
+type stageRequest struct {
+	ListFileName      string `json:"listFileName"`
+	NumberOfQuestions int    `json:"numberOfQuestions"`
+	MaxAnswerTimeSec  int    `json:"maxAnswerTimeSec"`
+	Eliminate         int    `json:"eliminate"`
+}
+
+type createTournamentRequest struct {
+	Name           string         `json:"name"`
+	ProfileToken   string         `json:"profileToken"`
+	TournamentName string         `json:"tournamentName"`
+	Stages         []stageRequest `json:"stages"`
+}
+
+type createTournamentResponse struct {
+	RoomKey      string                  `json:"roomKey"`
+	PlayerKey    string                  `json:"playerKey"`
+	PlayerSecret string                  `json:"playerSecret"`
+	ProfileId    string                  `json:"profileId,omitempty"`
+	DisplayToken string                  `json:"displayToken"`
+	ExportToken  string                  `json:"exportToken"`
+	Stage        contest.TournamentStage `json:"stage"`
+}
+
+func (r *roomContainer) createTournament(message json.RawMessage, address string) (*rpcRequestContext, error) {
+	request := parseMessage[createTournamentRequest](message)
+	playerProfile, name, err := r.resolveProfile(request.ProfileToken, request.Name)
+	if err != nil {
+		return nil, err
+	}
+	if len(name) == 0 {
+		return nil, fmt.Errorf("a player name must not be empty")
+	}
+	if len(request.TournamentName) > 50 {
+		return nil, fmt.Errorf("the tournament name must not be longer than 50 characters")
+	}
+	if len(request.Stages) == 0 || len(request.Stages) > maxTournamentStages {
+		return nil, fmt.Errorf("a tournament must have between 1 and %d stages", maxTournamentStages)
+	}
+	stages := make([]contest.Stage, 0, len(request.Stages))
+	for index, stage := range request.Stages {
+		streetList, err := geodata.ReadStreetList(filepath.Base(stage.ListFileName))
+		if err != nil {
+			return nil, fmt.Errorf("could not load street list of stage %d: %s", index+1, err)
+		}
+		if stage.NumberOfQuestions == 0 {
+			stage.NumberOfQuestions = defaultStageQuestions
+		}
+		if stage.MaxAnswerTimeSec == 0 {
+			stage.MaxAnswerTimeSec = defaultStageAnswerTimeSec
+		}
+		if stage.NumberOfQuestions < 1 || stage.NumberOfQuestions > 100 {
+			return nil, fmt.Errorf("stage %d must have between 1 and 100 questions", index+1)
+		}
+		if stage.MaxAnswerTimeSec < 10 {
+			return nil, fmt.Errorf("the answer time of stage %d must be at least 10 seconds", index+1)
+		}
+		if stage.Eliminate < 0 {
+			return nil, fmt.Errorf("the number of eliminated players of stage %d must not be negative", index+1)
+		}
+		stages = append(
+			stages, contest.Stage{
+				StreetList:        streetList,
+				NumberOfQuestions: stage.NumberOfQuestions,
+				MaxAnswerTime:     time.Duration(stage.MaxAnswerTimeSec) * time.Second,
+				Eliminate:         stage.Eliminate,
+			},
+		)
+	}
+	return &rpcRequestContext{
+		process: func() (any, error) {
+			room := r.newRoom()
+			room.Lock()
+			player := room.Join(name, address)
+			room.SetProfile(player.Key, playerProfile.Id)
+			tournament := contest.NewTournament(room, request.TournamentName, stages, player.Key)
+			stage := tournament.Stage()
+			room.Unlock()
+			r.Lock()
+			r.openRooms[room.Key()] = room
+			r.tournaments[room.Key()] = tournament
+			r.Unlock()
+			log.Printf("Created tournament in room \"%s\" with %d stages from player \"%s\" (\"%s\").", room.Key(), len(stages), player.Key, player.Name)
+			return createTournamentResponse{
+				RoomKey:      room.Key(),
+				PlayerKey:    player.Key,
+				PlayerSecret: player.Secret,
+				ProfileId:    playerProfile.Id,
+				DisplayToken: room.DisplayToken(),
+				ExportToken:  room.ExportToken(),
+				Stage:        stage,
+			}, nil
+		},
+	}, nil
+}
+
+type tournamentResponse struct {
+	Name      string                       `json:"name"`
+	Stage     contest.TournamentStage      `json:"stage"`
+	Standings []contest.TournamentStanding `json:"standings"`
+	Finished  bool                         `json:"finished"`
+}
+
+func (r *roomContainer) getTournament(message json.RawMessage, address string) (*rpcRequestContext, error) {
+	request := parseMessage[startGameRequest](message)
+	r.RLock()
+	room, roomOk := r.openRooms[request.RoomKey]
+	tournament, ok := r.tournaments[request.RoomKey]
+	r.RUnlock()
+	if !roomOk || !ok {
+		return nil, fmt.Errorf("tournament with key \"%s\" not found", request.RoomKey)
+	}
+	room.Lock()
+	return &rpcRequestContext{
+		process: func() (any, error) {
+			return tournamentResponse{
+				Name:      tournament.Name(),
+				Stage:     tournament.Stage(),
+				Standings: tournament.Standings(),
+				Finished:  tournament.Finished(),
+			}, nil
+		},
+		release: unlockRoom(room),
+	}, nil
+}
+
+func (r *roomContainer) advanceTournament(message json.RawMessage, address string) (*rpcRequestContext, error) {
+	request := parseMessage[startGameRequest](message)
+	room, err := r.validatePermission(
+		request.RoomKey, request.PlayerKey, request.PlayerSecret, contest.StartGamePermission,
+	)
+	if err != nil {
+		return &rpcRequestContext{release: unlockRoom(room)}, err
+	}
+	tournament, ok := r.tournament(request.RoomKey)
+	if !ok {
+		return &rpcRequestContext{release: unlockRoom(room)}, fmt.Errorf(
+			"room \"%s\" does not host a tournament", request.RoomKey,
+		)
+	}
+	if tournament.Finished() {
+		return &rpcRequestContext{release: unlockRoom(room)}, fmt.Errorf("the tournament is already finished")
+	}
+	if !tournament.CanAdvance() {
+		return &rpcRequestContext{release: unlockRoom(room)}, fmt.Errorf(
+			"the current stage of the tournament has not finished yet",
+		)
+	}
+	return &rpcRequestContext{
+		process: func() (any, error) {
+			tournament.Advance(request.PlayerKey)
+			stage := tournament.Stage()
+			log.Printf("Tournament in room \"%s\" advanced to stage %d.", room.Key(), stage.Stage+1)
+			return stage, nil
+		},
+		release: unlockRoom(room),
+	}, nil
+}
+
+func (r *roomContainer) tournament(roomKey string) (*contest.Tournament, bool) {
+	r.RLock()
+	defer r.RUnlock()
+	tournament, ok := r.tournaments[roomKey]
+	return tournament, ok
+}
+
+func (r *roomContainer) tournamentStarted(roomKey string) bool {
+	tournament, ok := r.tournament(roomKey)
+	return ok && tournament.Started()
+}
@@ -31,4 +31,9 @@ type Options struct {
 	DataProtectionFile string
 	Version            string
 	Seed               string
+	HistoryFile        string
+	ProfileFile        string
+	RatingFile         string
+	DailyFile          string
+	ChallengeFile      string
 }
@@ -2,36 +2,107 @@ package webapi
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"github.com/fafeitsch/city-knowledge-contest/backend/challenge"
 	"github.com/fafeitsch/city-knowledge-contest/backend/contest"
 	"github.com/fafeitsch/city-knowledge-contest/backend/geodata"
+	"github.com/fafeitsch/city-knowledge-contest/backend/history"
+	"github.com/fafeitsch/city-knowledge-contest/backend/profile"
+	"github.com/fafeitsch/city-knowledge-contest/backend/rating"
 	"io/ioutil"
 	"log"
+	"net"
 	"net/http"
 	"net/url"
 	"runtime/debug"
 	"strings"
+	"time"
 )
 
 type RpcServer struct {
 	methods       map[string]rpcHandler
 	options       Options
 	upgrader      func(w http.ResponseWriter, req *http.Request) error
-	roomContainer roomContainer
+	roomContainer *roomContainer
 }
 
 func New(options Options) *RpcServer {
-	roomContainer := roomContainer{openRooms: make(map[string]*contest.Room), seed: options.Seed}
+	store, err := history.Open(options.HistoryFile)
+	if err != nil {
+		log.Printf("could not open game history, finished games will not be persisted: %v", err)
+		store, _ = history.Open("")
+	}
+	profiles, err := profile.Open(options.ProfileFile)
+	if err != nil {
+		log.Printf("could not open profiles, profiles will not be persisted: %v", err)
+		profiles, _ = profile.Open("")
+	}
+	ratings, err := rating.Open(options.RatingFile)
+	if err != nil {
+		log.Printf("could not open ratings, rating changes will not be persisted: %v", err)
+		ratings, _ = rating.Open("")
+	}
+	daily, err := challenge.OpenDaily(options.DailyFile)
+	if err != nil {
+		log.Printf("could not open daily challenge results, results will not be persisted: %v", err)
+		daily, _ = challenge.OpenDaily("")
+	}
+	friends, err := challenge.OpenFriends(options.ChallengeFile)
+	if err != nil {
+		log.Printf("could not open friend challenges, challenges will not be persisted: %v", err)
+		friends, _ = challenge.OpenFriends("")
+	}
+	roomContainer := &roomContainer{
+		openRooms:   make(map[string]*contest.Room),
+		seed:        options.Seed,
+		attempts:    &attemptLimiter{attempts: make(map[string][]time.Time)},
+		lobby:       &lobby{subscribers: make(map[*websocketNotifier]bool)},
+		history:     store,
+		profiles:    profiles,
+		ratings:     ratings,
+		daily:       daily,
+		friends:     friends,
+		tournaments: make(map[string]*contest.Tournament),
+	}
 	roomContainer.startRoomCleaner()
+	roomContainer.startLobbyFeed()
 	methods := map[string]rpcHandler{
 		"createRoom":              roomContainer.createRoom,
 		"updateRoom":              roomContainer.updateRoom,
 		"joinRoom":                roomContainer.joinRoom,
+		"spectateRoom":            roomContainer.spectateRoom,
+		"becomePlayer":            roomContainer.becomePlayer,
 		"startGame":               roomContainer.startGame,
+		"rematch":                 roomContainer.rematch,
+		"getGameSummary":          roomContainer.getGameSummary,
+		"getLeaderboard":          roomContainer.getLeaderboard,
+		"createProfile":           roomContainer.createProfile,
+		"getProfile":              roomContainer.getProfile,
+		"updateProfile":           roomContainer.updateProfile,
+		"deleteProfile":           roomContainer.deleteProfile,
+		"getRatings":              roomContainer.getRatings,
+		"startDailyChallenge":     roomContainer.startDailyChallenge,
+		"getDailyChallenge":       roomContainer.getDailyChallenge,
+		"createChallenge":         roomContainer.createChallenge,
+		"getChallenge":            roomContainer.getChallenge,
+		"playChallenge":           roomContainer.playChallenge,
+		"startGhostGame":          roomContainer.startGhostGame,
+		"createTournament":        roomContainer.createTournament,
+		"getTournament":           roomContainer.getTournament,
+		"advanceTournament":       roomContainer.advanceTournament,
 		"leaveGame":               roomContainer.leaveGame,
 		"kickPlayer":              roomContainer.kickPlayer,
 		"answerQuestion":          roomContainer.answerQuestion,
 		"advanceGame":             roomContainer.advanceGame,
+		"voteReady":               roomContainer.voteReady,
+		"pauseGame":               roomContainer.pauseGame,
+		"resumeGame":              roomContainer.resumeGame,
+		"transferHost":            roomContainer.transferHost,
+		"delegatePermissions":     roomContainer.delegatePermissions,
+		"createInvite":            roomContainer.createInvite,
+		"listPublicRooms":         roomContainer.listPublicRooms,
+		"quickMatch":              roomContainer.quickMatch,
 		"getAvailableStreetLists": listStreetListFiles,
 		"getLegalInformation":     getLegalInformation(options),
 	}
@@ -39,6 +110,12 @@ func New(options Options) *RpcServer {
 		methods: methods,
 		options: options,
 		upgrader: func(resp http.ResponseWriter, req *http.Request) error {
+			if strings.HasPrefix(req.URL.Path, "/display/") {
+				return roomContainer.upgradeToDisplay(resp, req, options)
+			}
+			if req.URL.Path == "/lobby" {
+				return roomContainer.upgradeToLobby(resp, req, options)
+			}
 			return roomContainer.upgradeToWebSocket(resp, req, options)
 		},
 		roomContainer: roomContainer,
@@ -72,7 +149,7 @@ func getLegalInformation(options Options) rpcHandler {
 	if err == nil {
 		nominatimServerBase = nominatimServerUrl.Host
 	}
-	return func(message json.RawMessage) (*rpcRequestContext, error) {
+	return func(message json.RawMessage, address string) (*rpcRequestContext, error) {
 		return &rpcRequestContext{
 			process: func() (any, error) {
 				return map[string]string{
@@ -103,8 +180,15 @@ func (r *RpcServer) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
 		}
 		return
 	}
-	if parts[1] != "rpc" && parts[1] != "ws" {
-		if parts[1] == "room" {
+	if len(parts) > 2 && parts[1] == "export" {
+		if r.options.AllowCors {
+			setCorsHeaders(resp)
+		}
+		r.roomContainer.serveExport(resp, req)
+		return
+	}
+	if parts[1] != "rpc" && parts[1] != "ws" && parts[1] != "display" && parts[1] != "lobby" {
+		if parts[1] == "room" || parts[1] == "challenge" {
 			req.URL.Path = "/"
 		}
 		fs := http.FileServer(http.Dir("./frontend"))
@@ -141,12 +225,17 @@ func (r *RpcServer) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
 		writeError(resp, -32601, request.Id, "the requested method \"%s\" was not found", request.Method)
 		return
 	}
-	rpcRequest, err := validator(request.Params)
+	rpcRequest, err := validator(request.Params, clientAddress(req))
 	if rpcRequest != nil && rpcRequest.release != nil {
 		defer rpcRequest.release()
 	}
 	if err != nil {
-		writeError(resp, -32602, request.Id, "the validation for method \"%s\" failed: %v", request.Method, err)
+		code := -32602
+		var coded codedError
+		if errors.As(err, &coded) {
+			code = coded.code
+		}
+		writeError(resp, code, request.Id, "the validation for method \"%s\" failed: %v", request.Method, err)
 		return
 	}
 	result, err := rpcRequest.process()
@@ -159,13 +248,22 @@ func (r *RpcServer) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
 	_ = json.NewEncoder(resp).Encode(response)
 }
 
+func clientAddress(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
+
 func setCorsHeaders(resp http.ResponseWriter) {
 	resp.Header().Set("Content-Type", "application/json")
 	resp.Header().Set("Access-Control-Allow-Origin", "*")
 	resp.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
 	resp.Header().Set(
 		"Access-Control-Allow-Headers",
-		"Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization",
+		"Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, "+
+			exportTokenHeader+", "+playerSecretHeader,
 	)
 }
 
@@ -176,6 +274,25 @@ func handlePanic(resp http.ResponseWriter, request Request) {
 	}
 }
 
+const (
+	roomFullErrorCode        = -32001
+	tooManyAttemptsErrorCode = -32002
+	accessDeniedErrorCode    = -32003
+)
+
+type codedError struct {
+	code int
+	err  error
+}
+
+func (c codedError) Error() string {
+	return c.err.Error()
+}
+
+func (c codedError) Unwrap() error {
+	return c.err
+}
+
 func writeError(resp http.ResponseWriter, code int, id *string, format string, params ...interface{}) {
 	msg := fmt.Sprintf(format, params...)
 	response := Response{
@@ -0,0 +1,120 @@
+package webapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fafeitsch/city-knowledge-contest/backend/contest"
+	"github.com/fafeitsch/city-knowledge-contest/backend/geodata"
+	"github.com/fafeitsch/city-knowledge-contest/backend/types"
+	"github.com/stretchr/testify/require"
+)
+
+const testStreet = "Main Street"
+
+var testSolution = types.Coordinate{Lat: 49.79, Lng: 9.93}
+
+func TestMain(m *testing.M) {
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(writer http.ResponseWriter, request *http.Request) {
+				if strings.HasPrefix(request.URL.Path, "/search") {
+					_, _ = writer.Write([]byte(`[{"lat":"49.79","lon":"9.93"}]`))
+					return
+				}
+				road := "Elsewhere"
+				if strings.HasPrefix(request.URL.Query().Get("lat"), "49.79") {
+					road = testStreet
+				}
+				_ = json.NewEncoder(writer).Encode(map[string]any{"address": map[string]string{"road": road}})
+			},
+		),
+	)
+	geodata.NominatimServer = server.URL
+	geodata.StreetListDirectory = "../../streetlists"
+	code := m.Run()
+	server.Close()
+	os.Exit(code)
+}
+
+func call[T any](t *testing.T, server *RpcServer, method string, params any) (T, *Error) {
+	payload, err := json.Marshal(params)
+	require.NoError(t, err)
+	body, err := json.Marshal(Request{Jsonrpc: "2.0", Method: method, Params: payload})
+	require.NoError(t, err)
+	request := httptest.NewRequest(http.MethodPost, "/rpc", bytes.NewReader(body))
+	request.RemoteAddr = "192.0.2.1:1234"
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, request)
+	var response Response
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+	var result T
+	if response.Error != nil {
+		return result, response.Error
+	}
+	require.NoError(t, json.Unmarshal(response.Result, &result))
+	return result, nil
+}
+
+func createTestRoom(t *testing.T, server *RpcServer, name string) createRoomResponse {
+	room, rpcErr := call[createRoomResponse](t, server, "createRoom", createRoomRequest{Name: name})
+	require.Nil(t, rpcErr)
+	return room
+}
+
+func openTestRoom(server *RpcServer, roomKey string) *contest.Room {
+	server.roomContainer.RLock()
+	defer server.roomContainer.RUnlock()
+	return server.roomContainer.openRooms[roomKey]
+}
+
+func playTestGame(t *testing.T, server *RpcServer, created createRoomResponse) *contest.Room {
+	t.Helper()
+	room := openTestRoom(server, created.RoomKey)
+	room.Lock()
+	options := room.Options()
+	options.StreetList = &geodata.StreetList{FileName: "test.json", Name: "Test", Streets: []string{testStreet}}
+	options.NumberOfQuestions = 1
+	options.MaxAnswerTime = 10 * time.Second
+	room.SetOptions(options, created.PlayerKey)
+	require.Empty(t, room.ConfigErrors())
+	room.Play(created.PlayerKey)
+	room.Unlock()
+	require.Eventually(
+		t, func() bool {
+			room.Lock()
+			defer room.Unlock()
+			return room.HasActiveQuestion(created.PlayerKey)
+		}, 10*time.Second, 10*time.Millisecond,
+	)
+	room.Lock()
+	_, err := room.AnswerQuestion(created.PlayerKey, testSolution)
+	room.Unlock()
+	require.NoError(t, err)
+	require.Eventually(
+		t, func() bool {
+			room.Lock()
+			defer room.Unlock()
+			if !room.CanBeAdvanced() {
+				return false
+			}
+			room.AdvanceToNextQuestion()
+			return true
+		}, 10*time.Second, 10*time.Millisecond,
+	)
+	require.Eventually(
+		t, func() bool {
+			room.Lock()
+			defer room.Unlock()
+			_, ok := room.Summary()
+			return ok
+		}, 10*time.Second, 10*time.Millisecond,
+	)
+	return room
+}
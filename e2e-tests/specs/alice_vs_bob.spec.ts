@@ -34,9 +34,9 @@ test('should let alice play versus bob two rounds', async ({browser}) => {
     await expect(startButton).toBeDisabled()
   }
 
-  await bob.getByTestId('select-streetlist').selectOption({label: 'Würzburg Altstadt'})
+  await alice.getByTestId('select-streetlist').selectOption({label: 'Würzburg Altstadt'})
 
-  await expect(alice.getByTestId(selectors.selectStreetList)).toHaveValue('wuerzburg-altstadt.json')
+  await expect(bob.getByTestId(selectors.selectStreetList)).toHaveValue('wuerzburg-altstadt.json')
 
   await expect(bob.getByTestId(selectors.startGameButton)).toBeEnabled()
   await expect(alice.getByTestId(selectors.startGameButton)).toBeEnabled()
@@ -46,12 +46,12 @@ test('should let alice play versus bob two rounds', async ({browser}) => {
 
   await expect(bob.getByTestId(selectors.numberOfQuestionsInput)).toHaveValue('2')
 
-  await bob.getByTestId(selectors.maxAnswerTimeInput).clear()
-  await bob.getByTestId(selectors.maxAnswerTimeInput).type('10')
+  await alice.getByTestId(selectors.maxAnswerTimeInput).clear()
+  await alice.getByTestId(selectors.maxAnswerTimeInput).type('10')
 
-  await expect(alice.getByTestId(selectors.maxAnswerTimeInput)).toHaveValue('10')
+  await expect(bob.getByTestId(selectors.maxAnswerTimeInput)).toHaveValue('10')
 
-  await bob.getByTestId(selectors.startGameButton).click();
+  await alice.getByTestId(selectors.startGameButton).click();
   await countDowns(users);
 
   //dummy click since drag and drop doesn't work in playwright yet
@@ -86,7 +86,7 @@ test('should let alice play versus bob two rounds', async ({browser}) => {
   await expect(bob.getByTestId(selectors.proceedGameButton)).toHaveCount(1)
   await expect(alice.getByTestId(selectors.proceedGameButton)).toHaveCount(1)
 
-  await bob.getByTestId('proceed-game-button').click()
+  await alice.getByTestId('proceed-game-button').click()
 
   await expect(bob.getByTestId(selectors.gameOverTitle)).toHaveText('Das Spiel ist leider vorbei.')
   await expect(alice.getByTestId(selectors.gameOverTitle)).toHaveText('Das Spiel ist leider vorbei.')
@@ -32,7 +32,7 @@ test('should be possible to kick player in waiting room', async ({browser}) => {
 test('should be possible to kick player in running game', async ({browser}) => {
   const {alice, bob} = await createRoom(browser);
 
-  await bob.getByTestId(selectors.startGameButton).click()
+  await alice.getByTestId(selectors.startGameButton).click()
 
   await countDowns([{page: alice}, {page: bob}])
 
@@ -28,7 +28,7 @@ test('it should be able to leave the game while in game mode', async ({browser})
 
 test('it should be able to join a game late', async ({browser}) => {
   const {alice, bob} = await createRoom(browser);
-  await bob.getByTestId(selectors.startGameButton).click()
+  await alice.getByTestId(selectors.startGameButton).click()
   await countDowns([{page: alice}, {page: bob}])
   await alice.mouse.click(400,400)
   await expect(alice.getByTestId(selectors.playerListEntry).nth(0)).toHaveText(/Alice 0 Punkte\n.*\d\d/)
@@ -20,7 +20,7 @@ export async function createRoom(browser: Browser) {
   await bob.getByTestId(selectors.userNameInput).fill('Bob');
   await bob.getByTestId(selectors.joinRoomButton).click();
 
-  await bob.getByTestId(selectors.selectStreetList).selectOption({label: 'Würzburg Altstadt'})
+  await alice.getByTestId(selectors.selectStreetList).selectOption({label: 'Würzburg Altstadt'})
   return {alice, bob};
 }
 